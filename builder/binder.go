@@ -0,0 +1,524 @@
+package builder
+
+import (
+	"encoding/xml"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/go-aegian/gowsdlsoap/builder/bindings"
+	"github.com/go-aegian/gowsdlsoap/builder/model"
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
+)
+
+// binder applies a bindings document. The rules about types and elements are resolved before the Go names are
+// allotted, the ones about fields once the types are modeled and the ones about operations along with the services.
+// Every selector has to select something, the errors of the rules of a stage being reported together.
+type binder struct {
+	b     *Builder
+	scope scope
+	errs  []string
+
+	skippedTypes    map[xml.Name]bool
+	skippedElements map[xml.Name]bool
+	fields          []*fieldRule
+	operations      map[xml.Name]map[string]string
+
+	// imports holds the packages episodes import, which the packages of the Go types of the bindings join
+	imports []*model.Import
+
+	// dropped holds the Go names of the types left out, skipped or depending on skipped ones
+	dropped map[string]bool
+}
+
+// fieldRule renames, tags or skips the field a selector selects in the struct of a type or an element.
+type fieldRule struct {
+	selector  *bindings.Selector
+	component xml.Name
+	name      string
+	tags      string
+	skip      bool
+}
+
+// newBinder resolves the rules of the bindings document of the builder, if any, claiming the names given to types
+// and elements and recording the Go types others are mapped to.
+func newBinder(b *Builder) (*binder, error) {
+	r := &binder{
+		b:               b,
+		skippedTypes:    make(map[xml.Name]bool),
+		skippedElements: make(map[xml.Name]bool),
+		operations:      make(map[xml.Name]map[string]string),
+		imports:         b.imported.packages,
+		dropped:         make(map[string]bool),
+	}
+
+	if b.bindings == nil {
+		return r, nil
+	}
+
+	r.scope = scope{xmlns: b.bindings.Xmlns}
+
+	for _, rule := range b.bindings.GoTypes {
+		r.goType(rule)
+	}
+
+	typeNames, elementNames := make(map[xml.Name]string), make(map[xml.Name]string)
+	for _, rule := range b.bindings.Types {
+		selector, name, ok := r.component(rule.Node)
+		switch {
+		case !ok:
+		case !token.IsIdentifier(rule.Name):
+			r.fail(rule.Node, "%q is no Go identifier", rule.Name)
+		case selector.Element:
+			elementNames[name] = rule.Name
+		default:
+			typeNames[name] = rule.Name
+		}
+	}
+
+	for _, rule := range b.bindings.Fields {
+		if rule.Name != "" && !token.IsIdentifier(rule.Name) {
+			r.fail(rule.Node, "%q is no Go identifier", rule.Name)
+			continue
+		}
+
+		r.field(rule.Node, &fieldRule{name: rule.Name, tags: rule.Tags})
+	}
+
+	for _, rule := range b.bindings.Skips {
+		if selector, err := bindings.ParseSelector(rule.Node); err == nil && len(selector.Steps) > 0 {
+			r.field(rule.Node, &fieldRule{skip: true})
+			continue
+		}
+
+		if selector, name, ok := r.component(rule.Node); ok && selector.Element {
+			r.skippedElements[name] = true
+		} else if ok {
+			r.skippedTypes[name] = true
+		}
+	}
+
+	for _, rule := range b.bindings.Operations {
+		r.operation(rule)
+	}
+
+	if err := b.symbols.bind(typeNames, elementNames, b.isPolymorphic, b.isSubstitutionGroupHead); err != nil {
+		r.errs = append(r.errs, err.Error())
+	}
+
+	return r, r.err()
+}
+
+// fail records the error of the rule of a selector.
+func (r *binder) fail(node, format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf("%s: %s", node, fmt.Sprintf(format, args...)))
+}
+
+// err returns the errors recorded, if any.
+func (r *binder) err() error {
+	if len(r.errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("bindings: %s", strings.Join(r.errs, "; "))
+}
+
+// resolve resolves the prefix of a selected name, which has to be declared by the bindings document.
+func (r *binder) resolve(node, name string) (xml.Name, bool) {
+	if i := strings.Index(name, ":"); i >= 0 {
+		if _, ok := r.scope.xmlns[name[:i]]; !ok {
+			r.fail(node, "prefix %s is not declared", name[:i])
+			return xml.Name{}, false
+		}
+	}
+
+	return r.scope.resolve(name), true
+}
+
+// component resolves a selector of a global type or element of the schemas.
+func (r *binder) component(node string) (*bindings.Selector, xml.Name, bool) {
+	selector, err := bindings.ParseSelector(node)
+	if err != nil {
+		r.errs = append(r.errs, err.Error())
+		return nil, xml.Name{}, false
+	}
+
+	if len(selector.Steps) > 0 {
+		r.fail(node, "selects no type or element")
+		return nil, xml.Name{}, false
+	}
+
+	name, ok := r.resolve(node, selector.Name)
+	if !ok {
+		return nil, xml.Name{}, false
+	}
+
+	if selector.Element {
+		if _, found, ok := r.b.symbols.elements.get(name); ok {
+			return selector, found, true
+		}
+	} else if _, found, ok := r.b.symbols.complexTypes.get(name); ok {
+		return selector, found, true
+	} else if _, found, ok := r.b.symbols.simpleTypes.get(name); ok {
+		return selector, found, true
+	}
+
+	r.fail(node, "selects nothing")
+	return nil, xml.Name{}, false
+}
+
+// goType maps a built-in XML Schema type, or a type or an element of the schemas, to a Go type, importing the
+// package declaring it.
+func (r *binder) goType(rule *bindings.GoType) {
+	expr, err := parser.ParseExpr(rule.Type)
+	if err != nil {
+		r.fail(rule.Node, "%q is no Go type", rule.Type)
+		return
+	}
+
+	if rule.Import != "" {
+		alias := qualifier(expr)
+		if alias == "" {
+			r.fail(rule.Node, "%s is not qualified by the name of the package %s", rule.Type, rule.Import)
+			return
+		}
+
+		if !r.importPackage(alias, rule.Import, rule.Type) {
+			r.fail(rule.Node, "%s is the name of another imported package than %s", alias, rule.Import)
+			return
+		}
+	}
+
+	if selector, err := bindings.ParseSelector(rule.Node); err == nil && !selector.Element && len(selector.Steps) == 0 {
+		if name, ok := r.resolve(rule.Node, selector.Name); ok && name.Space == soap.XmlNsSoapXsd {
+			r.b.symbols.goTypes[name] = rule.Type
+			return
+		}
+	}
+
+	if selector, name, ok := r.component(rule.Node); ok && selector.Element {
+		r.b.symbols.elementGoTypes[name] = rule.Type
+	} else if ok {
+		r.b.symbols.goTypes[name] = rule.Type
+	}
+}
+
+// qualifier returns the name of the package a Go type is declared by, or an empty string for the types of the
+// generated package.
+func qualifier(expr ast.Expr) string {
+	var name string
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok && name == "" {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				name = ident.Name
+			}
+		}
+
+		return name == ""
+	})
+
+	return name
+}
+
+// generatedImports are the packages the generated files import by themselves, by name, which no other package can be
+// imported as.
+var generatedImports = map[string]string{
+	"context": "context",
+	"proxy":   "github.com/go-aegian/gowsdlsoap/proxy",
+	"soap":    "github.com/go-aegian/gowsdlsoap/builder/soap",
+	"time":    "time",
+	"xml":     "encoding/xml",
+	"xsd":     "github.com/go-aegian/gowsdlsoap/builder/xsd",
+}
+
+// importPackage imports a package under an alias, telling whether the alias is free for it.
+func (r *binder) importPackage(alias, path, goType string) bool {
+	if imported, ok := generatedImports[alias]; ok && imported != path {
+		return false
+	}
+
+	for _, i := range r.imports {
+		if i.Alias == alias {
+			return i.Path == path
+		}
+	}
+
+	r.imports = append(r.imports, &model.Import{Alias: alias, Path: path, Type: goType})
+
+	return true
+}
+
+// field records a rule about the field of a struct, resolving the type or element the struct is generated for.
+func (r *binder) field(node string, rule *fieldRule) {
+	selector, err := bindings.ParseSelector(node)
+	if err != nil {
+		r.errs = append(r.errs, err.Error())
+		return
+	}
+
+	if len(selector.Steps) == 0 {
+		r.fail(node, "selects no field")
+		return
+	}
+
+	component := *selector
+	component.Steps = nil
+
+	if _, name, ok := r.component(component.String()); ok {
+		rule.selector, rule.component = selector, name
+		r.fields = append(r.fields, rule)
+	}
+}
+
+// operation records the name given to an operation of a port type.
+func (r *binder) operation(rule *bindings.Operation) {
+	selector, err := bindings.ParseSelector(rule.Node)
+	if err != nil {
+		r.errs = append(r.errs, err.Error())
+		return
+	}
+
+	if !token.IsIdentifier(rule.Name) {
+		r.fail(rule.Node, "%q is no Go identifier", rule.Name)
+		return
+	}
+
+	name, ok := r.resolve(rule.Node, selector.Name)
+	if !ok {
+		return
+	}
+
+	if selector.Element || len(selector.Steps) != 1 || name.Space != "" && name.Space != r.b.wsdl.TargetNamespace {
+		r.fail(rule.Node, "selects no operation")
+		return
+	}
+
+	for _, portType := range r.b.wsdl.PortTypes {
+		for _, op := range portType.Operations {
+			if portType.Name == name.Local && op.Name == selector.Steps[0] {
+				if r.operations[name] == nil {
+					r.operations[name] = make(map[string]string)
+				}
+
+				r.operations[name][op.Name] = rule.Name
+				return
+			}
+		}
+	}
+
+	r.fail(rule.Node, "selects nothing")
+}
+
+// operationName returns the name given to an operation of a port type, if any.
+func (r *binder) operationName(portType, operation string) (string, bool) {
+	name, ok := r.operations[xml.Name{Space: r.b.wsdl.TargetNamespace, Local: portType}][operation]
+	if !ok {
+		name, ok = r.operations[xml.Name{Local: portType}][operation]
+	}
+
+	return name, ok
+}
+
+// omits tells whether no Go type is generated for a type or an element, which is either skipped or mapped to
+// another Go type.
+func (r *binder) omits(name xml.Name, element bool) bool {
+	if element {
+		_, mapped := r.b.symbols.elementGoTypes[name]
+		return mapped || r.skippedElements[name]
+	}
+
+	_, mapped := r.b.symbols.goTypes[name]
+	return mapped || r.skippedTypes[name]
+}
+
+// bindFields renames, tags and skips the fields the field rules select, then leaves out the fields holding skipped
+// types, and the types embedding or defined by them.
+func (r *binder) bindFields(pkg *model.Package) error {
+	for _, rule := range r.fields {
+		goName := r.b.goTypeName(rule.component)
+		if rule.selector.Element {
+			goName = r.b.goElementName(rule.component)
+		}
+
+		t := pkg.Type(goName)
+		if t == nil {
+			r.fail(rule.selector.String(), "selects a type which is not generated")
+			continue
+		}
+
+		fields, i := r.selectField(pkg, &t.Fields, rule.selector)
+		if fields == nil {
+			r.fail(rule.selector.String(), "selects nothing")
+			continue
+		}
+
+		f := (*fields)[i]
+		switch {
+		case rule.skip:
+			*fields = append((*fields)[:i], (*fields)[i+1:]...)
+		case rule.name != "" && rule.name != f.Name && fieldTaken(*fields, rule.name):
+			r.fail(rule.selector.String(), "%s is already taken in %s", rule.name, t.Name)
+		case rule.name != "":
+			f.Name = rule.name
+		}
+
+		if rule.tags != "" && !rule.skip {
+			f.Tag += " " + rule.tags
+		}
+	}
+
+	r.dropSkipped(pkg)
+
+	return r.err()
+}
+
+// selectField finds the field a selector selects, following its steps through the anonymous structs and the
+// embedded base types, and returns the fields holding it along with its index.
+func (r *binder) selectField(pkg *model.Package, fields *[]*model.Field, selector *bindings.Selector) (*[]*model.Field, int) {
+	for n, step := range selector.Steps {
+		holder, i := r.findField(pkg, fields, selector.String(), step)
+		if holder == nil || n == len(selector.Steps)-1 {
+			return holder, i
+		}
+
+		fields = &(*holder)[i].Struct
+	}
+
+	return nil, 0
+}
+
+func (r *binder) findField(pkg *model.Package, fields *[]*model.Field, node, step string) (*[]*model.Field, int) {
+	local, attribute := bindings.Attribute(step)
+
+	name, ok := r.resolve(node, local)
+	if !ok {
+		return nil, 0
+	}
+
+	for i, f := range *fields {
+		if f.Embedded || f.Name == "XMLName" || attribute != strings.Contains(f.Tag, ",attr") {
+			continue
+		}
+
+		tagged := tagName(f.Tag)
+		if tagged.Local == name.Local && (!strings.Contains(local, ":") || tagged.Space == name.Space) {
+			return fields, i
+		}
+	}
+
+	for _, f := range *fields {
+		if base := pkg.Type(f.Selector()); f.Embedded && base != nil {
+			if holder, i := r.findField(pkg, &base.Fields, node, step); holder != nil {
+				return holder, i
+			}
+		}
+	}
+
+	return nil, 0
+}
+
+func fieldTaken(fields []*model.Field, name string) bool {
+	if name == "XMLName" || containsString(methodNames, name) {
+		return true
+	}
+
+	for _, f := range fields {
+		if f.Selector() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dropSkipped leaves out the fields holding values of skipped types, and the types embedding them or defined by
+// them, until none refers to a skipped type.
+func (r *binder) dropSkipped(pkg *model.Package) {
+	skipped := r.dropped
+	for name := range r.skippedTypes {
+		skip(skipped, r.b.goTypeName(name), r.b.isPolymorphic(name), polymorphicNames)
+	}
+
+	for name := range r.skippedElements {
+		skip(skipped, r.b.goElementName(name), r.b.isSubstitutionGroupHead(name), headNames)
+	}
+
+	for dropped := true; dropped; {
+		dropped = false
+
+		var types []*model.Type
+		for _, t := range pkg.Types {
+			if skipped[t.Name] {
+				continue
+			}
+
+			if skipped[model.BaseType(t.Underlying)] || embedsSkipped(t.Fields, skipped) {
+				skipped[t.Name], dropped = true, true
+				continue
+			}
+
+			t.Fields = dropSkippedFields(t.Fields, skipped)
+			types = append(types, t)
+		}
+
+		pkg.Types = types
+	}
+}
+
+// bindServices leaves out the fields of the message types of the operations holding skipped types, the messages
+// of an operation being required.
+func (r *binder) bindServices(services []*model.Service) error {
+	for _, service := range services {
+		for _, operation := range service.Operations {
+			for _, t := range operation.Types {
+				t.Fields = dropSkippedFields(t.Fields, r.dropped)
+			}
+
+			for _, message := range []string{operation.Request, operation.Response} {
+				if r.dropped[model.BaseType(message)] {
+					r.errs = append(r.errs, fmt.Sprintf("%s is skipped but is a message of %s.%s", message, service.Name, operation.Name))
+				}
+			}
+		}
+	}
+
+	return r.err()
+}
+
+func skip(skipped map[string]bool, name string, derives bool, derived []string) {
+	skipped[name] = true
+
+	if derives {
+		for _, format := range derived {
+			skipped[fmt.Sprintf(format, name)] = true
+		}
+	}
+}
+
+func embedsSkipped(fields []*model.Field, skipped map[string]bool) bool {
+	for _, f := range fields {
+		if f.Embedded && skipped[model.BaseType(f.Type)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dropSkippedFields(fields []*model.Field, skipped map[string]bool) []*model.Field {
+	var kept []*model.Field
+
+	for _, f := range fields {
+		if !f.Embedded && skipped[model.BaseType(f.Type)] {
+			continue
+		}
+
+		f.Struct = dropSkippedFields(f.Struct, skipped)
+		kept = append(kept, f)
+	}
+
+	return kept
+}
@@ -0,0 +1,9 @@
+package builder
+
+// WithBindings is an Option to customize the generated code with the bindings document read from file, which
+// renames types, fields and operations, maps types to other Go types, adds struct tags and skips types and fields.
+func WithBindings(file string) Option {
+	return func(o *Options) {
+		o.Bindings = file
+	}
+}
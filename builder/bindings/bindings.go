@@ -0,0 +1,91 @@
+package bindings
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+)
+
+// Bindings customize the code generated for the components of the schemas and for the operations of a WSDL
+// document, in the spirit of JAXB binding files. Rules address what they customize with selectors, the prefixes of
+// which are resolved against the namespaces the bindings document declares on its root element.
+//
+//	<bindings xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:o="http://example.com/orders.xsd">
+//		<goType node="xs:decimal" type="decimal.Decimal" import="github.com/shopspring/decimal"/>
+//		<type node="o:order_item" name="LineItem"/>
+//		<field node="o:Order/@id" name="Reference" tags='db:"reference"'/>
+//		<operation node="tns:Orders/PlaceOrder" name="Place"/>
+//		<skip node="element(o:Legacy)"/>
+//	</bindings>
+type Bindings struct {
+	Xmlns      map[string]string `xml:"-"`
+	GoTypes    []*GoType         `xml:"goType"`
+	Types      []*Type           `xml:"type"`
+	Fields     []*Field          `xml:"field"`
+	Operations []*Operation      `xml:"operation"`
+	Skips      []*Skip           `xml:"skip"`
+}
+
+// GoType maps a built-in XML Schema type, everywhere it is used, or a type or element of the schemas, which is then
+// not generated, to a Go type. Types of other packages are qualified by the name of their package, imported from
+// Import.
+type GoType struct {
+	Node   string `xml:"node,attr"`
+	Type   string `xml:"type,attr"`
+	Import string `xml:"import,attr"`
+}
+
+// Type renames the Go type generated for a type or an element.
+type Type struct {
+	Node string `xml:"node,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// Field renames a field of a struct, or adds struct tags to it.
+type Field struct {
+	Node string `xml:"node,attr"`
+	Name string `xml:"name,attr"`
+	Tags string `xml:"tags,attr"`
+}
+
+// Operation renames the method generated for an operation of a port type.
+type Operation struct {
+	Node string `xml:"node,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// Skip leaves out the Go type of a type or an element, along with the fields holding it, or a field of a struct.
+type Skip struct {
+	Node string `xml:"node,attr"`
+}
+
+// Read reads a bindings document.
+func Read(file string) (*Bindings, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	b := new(Bindings)
+	if err := xml.Unmarshal(data, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler, keeping the namespaces the root element declares.
+func (b *Bindings) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	b.Xmlns = make(map[string]string)
+
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			b.Xmlns[attr.Name.Local] = attr.Value
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			b.Xmlns[""] = attr.Value
+		}
+	}
+
+	type rules Bindings
+	return d.DecodeElement((*rules)(b), &start)
+}
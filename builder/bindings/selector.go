@@ -0,0 +1,60 @@
+package bindings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selector addresses what a rule customizes, in an XPath-like syntax. Its first step is the prefixed name of a
+// global type, of a global element when written element(name), or of a port type. The following steps are the
+// names of the local elements leading to a field, attributes being prefixed by @, or the name of an operation.
+//
+//	o:Order            the type o:Order
+//	element(o:Order)   the element o:Order
+//	o:Order/item/@sku  the sku attribute of the item element of the type o:Order
+//	tns:Orders/Place   the Place operation of the port type tns:Orders
+type Selector struct {
+	Element bool
+	Name    string
+	Steps   []string
+}
+
+// ParseSelector parses a selector. Leading slashes are allowed, as in absolute XPath expressions.
+func ParseSelector(node string) (*Selector, error) {
+	steps := strings.Split(strings.TrimLeft(strings.TrimSpace(node), "/"), "/")
+
+	s := &Selector{Name: steps[0], Steps: steps[1:]}
+	if strings.HasPrefix(s.Name, "element(") && strings.HasSuffix(s.Name, ")") {
+		s.Element, s.Name = true, strings.TrimSuffix(strings.TrimPrefix(s.Name, "element("), ")")
+	}
+
+	if s.Name == "" || strings.ContainsAny(s.Name, "()@") {
+		return nil, fmt.Errorf("selector %q does not start with the name of a type, an element or a port type", node)
+	}
+
+	for _, step := range s.Steps {
+		if strings.TrimPrefix(step, "@") == "" {
+			return nil, fmt.Errorf("selector %q has an empty step", node)
+		}
+	}
+
+	return s, nil
+}
+
+// Attribute tells whether a step selects an attribute, returning its name.
+func Attribute(step string) (string, bool) {
+	if strings.HasPrefix(step, "@") {
+		return step[1:], true
+	}
+
+	return step, false
+}
+
+func (s *Selector) String() string {
+	name := s.Name
+	if s.Element {
+		name = "element(" + name + ")"
+	}
+
+	return strings.Join(append([]string{name}, s.Steps...), "/")
+}
@@ -15,19 +15,23 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
 	"time"
 	"unicode"
 
+	"github.com/go-aegian/gowsdlsoap/builder/bindings"
+	"github.com/go-aegian/gowsdlsoap/builder/episode"
+	"github.com/go-aegian/gowsdlsoap/builder/model"
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
 	"github.com/go-aegian/gowsdlsoap/builder/templates"
 	"github.com/go-aegian/gowsdlsoap/builder/wsdl"
+	"github.com/go-aegian/gowsdlsoap/builder/wsdl2"
 	"github.com/go-aegian/gowsdlsoap/builder/xsd"
 )
 
-const maxRecursion uint8 = 20
-
 var basicTypes = map[string]string{
 	"string":      "string",
 	"float32":     "float32",
@@ -103,35 +107,6 @@ var reservedWords = map[string]string{
 	"var":         "var_",
 }
 
-var reservedWordsInAttr = map[string]string{
-	"break":       "break_",
-	"default":     "default_",
-	"func":        "func_",
-	"interface":   "interface_",
-	"select":      "select_",
-	"case":        "case_",
-	"defer":       "defer_",
-	"go":          "go_",
-	"map":         "map_",
-	"struct":      "struct_",
-	"chan":        "chan_",
-	"else":        "else_",
-	"goto":        "goto_",
-	"package":     "package_",
-	"switch":      "switch_",
-	"const":       "const_",
-	"fallthrough": "fallthrough_",
-	"if":          "if_",
-	"range":       "range_",
-	"type":        "type_",
-	"continue":    "continue_",
-	"for":         "for_",
-	"import":      "import_",
-	"return":      "return_",
-	"var":         "var_",
-	"string":      "string_",
-}
-
 var timeout = 30 * time.Second
 
 var cacheDir = filepath.Join(os.TempDir(), "gowsdlsoap-cache")
@@ -146,17 +121,31 @@ func init() {
 
 // Builder defines the struct for WSDL generator.
 type Builder struct {
-	location              *location
-	pkg                   string
-	skipTls               bool
-	makePublicFn          func(string) string
-	wsdl                  *wsdl.WSDL
-	xsdExternals          map[string]bool
-	currentRecursionLevel uint8
-	currentNamespace      string
+	location      *location
+	pkg           string
+	skipTls       bool
+	exported      bool
+	naming        Naming
+	bindings      *bindings.Bindings
+	binder        *binder
+	importPath    string
+	episodes      []*episode.Episode
+	imported      *imports
+	wsdl          *wsdl.WSDL
+	wsdlImports   map[string]bool
+	xsdExternals  map[string]bool
+	symbols       *symbols
+	derivations   map[xml.Name]xml.Name
+	substitutions map[xml.Name]xml.Name
+	baseTypes     map[xml.Name]bool
+	headElements  map[xml.Name]bool
+
+	// unqualifiedElements is set once an element in no namespace is met under one in a namespace
+	unqualifiedElements bool
 }
 
-func New(file, pkg string, ignoreTLS bool, exportAllTypes bool) (*Builder, error) {
+// New creates the builder of the Go proxy of a WSDL document, its types being exported when exportAllTypes is set.
+func New(file, pkg string, ignoreTLS bool, exportAllTypes bool, opt ...Option) (*Builder, error) {
 	file = strings.TrimSpace(file)
 	if file == "" {
 		return nil, errors.New("WSDL file is required to generate Go proxy")
@@ -167,9 +156,9 @@ func New(file, pkg string, ignoreTLS bool, exportAllTypes bool) (*Builder, error
 		pkg = "soapProxy"
 	}
 
-	makePublicFn := func(id string) string { return id }
-	if exportAllTypes {
-		makePublicFn = makePublic
+	opts := DefaultOptions
+	for _, o := range opt {
+		o(&opts)
 	}
 
 	r, err := NewLocation(file)
@@ -177,30 +166,44 @@ func New(file, pkg string, ignoreTLS bool, exportAllTypes bool) (*Builder, error
 		return nil, err
 	}
 
+	var rules *bindings.Bindings
+	if opts.Bindings != "" {
+		if rules, err = bindings.Read(opts.Bindings); err != nil {
+			return nil, err
+		}
+	}
+
+	var episodes []*episode.Episode
+	for _, file := range opts.Episodes {
+		e, err := episode.Read(file)
+		if err != nil {
+			return nil, err
+		}
+
+		episodes = append(episodes, e)
+	}
+
 	return &Builder{
-		location:     r,
-		pkg:          pkg,
-		skipTls:      ignoreTLS,
-		makePublicFn: makePublicFn,
+		location:   r,
+		pkg:        pkg,
+		skipTls:    ignoreTLS,
+		exported:   exportAllTypes,
+		naming:     opts.Naming,
+		bindings:   rules,
+		importPath: opts.Episode,
+		episodes:   episodes,
 	}, nil
 }
 
-// Build initiates the code generation process by starting two goroutines:
-//   generate types
-//   generate operations
+// Build resolves the WSDL document into the model of the generated code, then renders the header, the types and
+// the operations from it. Types and operations are rendered concurrently, the model being only read.
 func (b *Builder) Build() (map[string][]byte, error) {
-	code := make(map[string][]byte)
-
-	err := b.unmarshal()
+	pkg, err := b.Model()
 	if err != nil {
 		return nil, err
 	}
 
-	// Process WSDL nodes
-	for _, schema := range b.wsdl.Types.Schemas {
-		NewXsdParser(schema, b.wsdl.Types.Schemas).parse()
-	}
-
+	var types, operations []byte
 	var wg sync.WaitGroup
 
 	wg.Add(1)
@@ -208,7 +211,7 @@ func (b *Builder) Build() (map[string][]byte, error) {
 		defer wg.Done()
 		var err error
 
-		code["types"], err = b.parseTypes()
+		types, err = parseTypes(pkg)
 		if err != nil {
 			log.Println("parseTypes", "error", err)
 		}
@@ -219,7 +222,7 @@ func (b *Builder) Build() (map[string][]byte, error) {
 		defer wg.Done()
 		var err error
 
-		code["operations"], err = b.parseOperations()
+		operations, err = parseOperations(pkg)
 		if err != nil {
 			log.Println("parseOperations", "error", err)
 		}
@@ -227,7 +230,15 @@ func (b *Builder) Build() (map[string][]byte, error) {
 
 	wg.Wait()
 
-	code["header"], err = b.parseHeader()
+	code := map[string][]byte{"types": types, "operations": operations}
+
+	if b.importPath != "" {
+		if code["episode"], err = b.episode(pkg); err != nil {
+			return nil, err
+		}
+	}
+
+	code["header"], err = parseHeader(pkg)
 	if err != nil {
 		log.Println(err)
 	}
@@ -235,15 +246,90 @@ func (b *Builder) Build() (map[string][]byte, error) {
 	return code, nil
 }
 
-// Method setNamespace sets (and returns) the currently active XML namespace.
-func (b *Builder) setNamespace(ns string) string {
-	b.currentNamespace = ns
-	return b.currentNamespace
+// Model loads the WSDL document and its schemas, and resolves them into the model the code is generated from.
+func (b *Builder) Model() (*model.Package, error) {
+	err := b.unmarshal()
+	if err != nil {
+		return nil, err
+	}
+
+	// Process WSDL nodes
+	for _, schema := range b.wsdl.Types.Schemas {
+		NewXsdParser(schema, b.symbols).parse()
+	}
+
+	b.derivations = b.findDerivations()
+	b.substitutions = b.findSubstitutions()
+	b.baseTypes = targets(b.derivations)
+	b.headElements = targets(b.substitutions)
+
+	// the types and elements episodes list are imported from the packages generated for them
+	b.importEpisodes()
+
+	// the names bindings give are claimed first, and the Go types they map types to are known before any type refers
+	// to them
+	if b.binder, err = newBinder(b); err != nil {
+		return nil, err
+	}
+
+	// types are named once known to be polymorphic or heads of substitution groups, the names derived from theirs
+	// being claimed along with them
+	b.symbols.name(b.wsdl.Types.Schemas, b.goName, b.isPolymorphic, b.isSubstitutionGroupHead)
+
+	pkg := &model.Package{Name: b.pkg, Imports: b.binder.imports}
+
+	var detached []*model.Type
+	for _, schema := range b.wsdl.Types.Schemas {
+		modeler := newTypesModeler(b, schema)
+		pkg.Types = append(pkg.Types, modeler.types()...)
+		detached = append(detached, modeler.detached...)
+	}
+
+	// elements sharing their Go type with a complex type extend that type
+	for _, extension := range detached {
+		if t := pkg.Type(extension.Name); t != nil {
+			t.SubstitutionGroupHead = t.SubstitutionGroupHead || extension.SubstitutionGroupHead
+			t.Markers = append(t.Markers, extension.Markers...)
+		}
+	}
+
+	if err = b.binder.bindFields(pkg); err != nil {
+		return nil, err
+	}
+
+	b.presets(pkg.Types)
+
+	pkg.SubstitutionGroupMembers = declared(pkg, b.substitutionGroupMembers())
+	pkg.XSITypes = declared(pkg, b.xsiTypes())
+	if pkg.HasWildcards() {
+		pkg.GlobalElements = b.globalElements(pkg)
+	}
+
+	if pkg.Services, err = b.services(); err != nil {
+		return nil, err
+	}
+
+	return pkg, nil
+}
+
+// presets adds the constants holding the default and fixed values of the fields of the structs, and names the
+// constructors setting them along with the constructors of the members of the unions.
+func (b *Builder) presets(types []*model.Type) {
+	for _, t := range types {
+		t.Constants = append(t.Constants, presetConstants(t.Name, "", t.Fields)...)
+
+		if len(t.Presets()) > 0 {
+			t.Constructor = b.symbols.reserve("New" + t.Name)
+		}
+
+		for _, member := range t.Union {
+			member.Constructor = b.symbols.reserve("New" + t.Name + member.Name)
+		}
+	}
 }
 
-// Method setNamespace returns the currently active XML namespace.
-func (b *Builder) getNamespace() string {
-	return b.currentNamespace
+func (b *Builder) wsdlScope() scope {
+	return scope{xmlns: b.wsdl.Xmlns, targetNamespace: b.wsdl.TargetNamespace}
 }
 
 func (b *Builder) readFile(loc *location) (data []byte, err error) {
@@ -258,28 +344,100 @@ func (b *Builder) readFile(loc *location) (data []byte, err error) {
 	return
 }
 
+// unmarshal loads the WSDL document, along with the documents it imports which are merged into it.
 func (b *Builder) unmarshal() error {
-	data, err := b.readFile(b.location)
+	b.wsdl = nil
+	b.wsdlImports = make(map[string]bool)
+	b.xsdExternals = make(map[string]bool)
+
+	if err := b.importWSDL(b.location, make(map[string]bool)); err != nil {
+		return err
+	}
+
+	b.symbols = newSymbols(b.wsdl.Types.Schemas)
+
+	return nil
+}
+
+// importWSDL reads a WSDL document, resolves its external schemas and recursively imports the WSDL documents it
+// refers to. Documents are loaded once, loading holds the chain of documents being imported to report cycles.
+func (b *Builder) importWSDL(loc *location, loading map[string]bool) error {
+	key := loc.String()
+	if loading[key] {
+		log.Printf("[WARN] Import cycle detected, %s is already being imported", key)
+		return nil
+	}
+
+	if b.wsdlImports[key] {
+		return nil
+	}
+
+	b.wsdlImports[key] = true
+
+	data, err := b.readFile(loc)
 	if err != nil {
 		return err
 	}
 
-	b.wsdl = new(wsdl.WSDL)
-	err = xml.Unmarshal(data, b.wsdl)
+	definitions, err := unmarshalWSDL(data)
 	if err != nil {
 		return err
 	}
 
-	for _, schema := range b.wsdl.Types.Schemas {
-		err = b.resolveExternal(schema, b.location)
+	if b.wsdl == nil {
+		b.wsdl = definitions
+	} else {
+		b.wsdl.Merge(definitions)
+	}
+
+	for _, schema := range definitions.Types.Schemas {
+		err = b.resolveExternal(schema, loc)
+		if err != nil {
+			return err
+		}
+	}
+
+	loading[key] = true
+	defer delete(loading, key)
+
+	for _, wsdlImport := range definitions.Imports {
+		if wsdlImport.Location == "" {
+			log.Printf("[WARN] Don't know where to find WSDL for %s", wsdlImport.Namespace)
+			continue
+		}
+
+		importLocation, err := loc.Parse(wsdlImport.Location)
 		if err != nil {
 			return err
 		}
+
+		if err = b.importWSDL(importLocation, loading); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// unmarshalWSDL reads WSDL 1.1 definitions, or a WSDL 2.0 description mapped onto the WSDL 1.1 model.
+func unmarshalWSDL(data []byte) (*wsdl.WSDL, error) {
+	if wsdl2.IsDescription(data) {
+		description := new(wsdl2.Description)
+		if err := xml.Unmarshal(data, description); err != nil {
+			return nil, err
+		}
+
+		return description.WSDL(), nil
+	}
+
+	definitions := new(wsdl.WSDL)
+	if err := xml.Unmarshal(data, definitions); err != nil {
+		return nil, err
+	}
+
+	return definitions, nil
+}
+
 func (b *Builder) resolveExternal(schema *xsd.Schema, loc *location) error {
 	download := func(base *location, ref string) error {
 		location, err := base.Parse(ref)
@@ -287,15 +445,12 @@ func (b *Builder) resolveExternal(schema *xsd.Schema, loc *location) error {
 			return err
 		}
 
+		// schemas are downloaded once, which also breaks the cycles of schemas importing each other
 		schemaKey := location.String()
-		if b.xsdExternals[location.String()] {
+		if b.xsdExternals[schemaKey] {
 			return nil
 		}
 
-		if b.xsdExternals == nil {
-			b.xsdExternals = make(map[string]bool, maxRecursion)
-		}
-
 		b.xsdExternals[schemaKey] = true
 
 		var data []byte
@@ -310,13 +465,9 @@ func (b *Builder) resolveExternal(schema *xsd.Schema, loc *location) error {
 			return err
 		}
 
-		if (len(newSchema.Includes) > 0 || len(newSchema.Imports) > 0) && maxRecursion > b.currentRecursionLevel {
-			b.currentRecursionLevel++
-
-			err = b.resolveExternal(newSchema, location)
-			if err != nil {
-				return err
-			}
+		err = b.resolveExternal(newSchema, location)
+		if err != nil {
+			return err
 		}
 
 		b.wsdl.Types.Schemas = append(b.wsdl.Types.Schemas, newSchema)
@@ -345,34 +496,33 @@ func (b *Builder) resolveExternal(schema *xsd.Schema, loc *location) error {
 	return nil
 }
 
-func (b *Builder) parseTypes() ([]byte, error) {
+func parseTypes(pkg *model.Package) ([]byte, error) {
+	return render("types", templates.Types, pkg)
+}
+
+func parseOperations(pkg *model.Package) ([]byte, error) {
+	return render("operations", templates.Operations, pkg)
+}
+
+func parseHeader(pkg *model.Package) ([]byte, error) {
+	return render("header", templates.Header, pkg)
+}
+
+// render executes a template of the generated code against the model, along with the declarations it shares with
+// the other templates.
+func render(name, text string, pkg *model.Package) ([]byte, error) {
 	funcMap := template.FuncMap{
-		"isBasicType":              isBasicType,
-		"toGoType":                 toGoType,
-		"stripAliasNSFromType":     stripAliasNSFromType,
-		"replaceReservedWords":     replaceReservedWords,
-		"replaceAttrReservedWords": replaceAttrReservedWords,
-		"normalize":                normalize,
-		"makeFieldPublic":          makePublic,
-		"comment":                  comment,
-		"goString":                 goString,
-		"isAbstract":               b.isAbstract,
-		"makePublic":               b.makePublicFn,
-		"findMessageType":          b.findMessageType,
-		"findNameByType":           b.findNameByType,
-		"stripPointerFromType":     stripPointerFromType,
-		"setNamespace":             b.setNamespace,
-		"getNamespace":             b.getNamespace,
-		"packageName":              b.packageName,
-		"getAliasNS":               getAliasNS,
-		"getNSFromType":            b.getNSFromType,
+		"comment":  comment,
+		"goString": goString,
+		"quote":    strconv.Quote,
 	}
 
-	data := new(bytes.Buffer)
+	tmpl := template.Must(template.New(name).Funcs(funcMap).Parse(text))
+	template.Must(tmpl.New("declarations").Parse(templates.Declarations))
 
-	tmpl := template.Must(template.New("types").Funcs(funcMap).Parse(templates.Types))
+	data := new(bytes.Buffer)
 
-	err := tmpl.Execute(data, b.wsdl.Types)
+	err := tmpl.ExecuteTemplate(data, name, pkg)
 	if err != nil {
 		return nil, err
 	}
@@ -380,198 +530,597 @@ func (b *Builder) parseTypes() ([]byte, error) {
 	return data.Bytes(), nil
 }
 
-func (b *Builder) parseOperations() ([]byte, error) {
-	funcMap := template.FuncMap{
-		"toGoType":             toGoType,
-		"stripAliasNSFromType": stripAliasNSFromType,
-		"replaceReservedWords": replaceReservedWords,
-		"normalize":            normalize,
-		"makePrivate":          makePrivate,
-		"packageName":          b.packageName,
-		"makePublic":           b.makePublicFn,
-		"findMessageType":      b.findMessageType,
-		"findSOAPAction":       b.findSOAPAction,
-		"findServiceAddress":   b.findServiceAddress,
+// isAbstract tells whether a complex type is abstract or, with checkParent, derives from an abstract type.
+func (b *Builder) isAbstract(name xml.Name, checkParent bool) bool {
+	if name.Space == soap.XmlNsSoapXsd {
+		return true
 	}
 
-	data := new(bytes.Buffer)
+	complexType, _, ok := b.symbols.complexTypes.get(name)
+	if !ok {
+		return false
+	}
 
-	tmpl := template.Must(template.New("operations").Funcs(funcMap).Parse(templates.Operations))
+	if complexType.Abstract || !checkParent || complexType.ComplexContent.Extension.Base == "" {
+		return complexType.Abstract
+	}
 
-	err := tmpl.Execute(data, b.wsdl.PortTypes)
-	if err != nil {
-		return nil, err
+	baseName := b.symbols.typeName(schemaScope(b.symbols.schemas[complexType]), complexType.ComplexContent.Extension.Base)
+	base, _, ok := b.symbols.complexTypes.get(baseName)
+
+	return ok && base.Abstract
+}
+
+// goTypeName returns the Go name of a simple or complex type.
+func (b *Builder) goTypeName(name xml.Name) string {
+	if goName, ok := b.symbols.typeNames[name]; ok {
+		return goName
 	}
 
-	return data.Bytes(), nil
+	return b.goName(name.Local)
 }
 
-func (b *Builder) parseHeader() ([]byte, error) {
-	funcMap := template.FuncMap{
-		"toGoType":             toGoType,
-		"stripAliasNSFromType": stripAliasNSFromType,
-		"replaceReservedWords": replaceReservedWords,
-		"normalize":            normalize,
-		"comment":              comment,
-		"makePublic":           b.makePublicFn,
-		"findMessageType":      b.findMessageType,
+// goElementName returns the Go name of the type generated for a global element.
+func (b *Builder) goElementName(name xml.Name) string {
+	if goName, ok := b.symbols.elementNames[name]; ok {
+		return goName
 	}
 
-	data := new(bytes.Buffer)
+	return b.goName(name.Local)
+}
 
-	tmpl := template.Must(template.New("header").Funcs(funcMap).Parse(templates.Header))
+// goName returns the Go name of a type, an element or an operation, exported when all types are.
+func (b *Builder) goName(name string) string {
+	return b.naming.identifier(name, b.exported)
+}
 
-	err := tmpl.Execute(data, b.pkg)
-	if err != nil {
-		return nil, err
+// fieldName returns the Go name of a struct field, which is always exported for encoding/xml to see it. Names of basic
+// types get a trailing underscore, makePublic leaving them as they are.
+func (b *Builder) fieldName(name string) string {
+	if isBasicType(name) {
+		name += "_"
 	}
 
-	return data.Bytes(), nil
+	return b.naming.identifier(name, true)
 }
 
-func (b *Builder) packageName() string {
-	return b.pkg
+func (b *Builder) findMessageType(message string) string {
+	msg := b.findMessage(message)
+	if msg == nil {
+		return ""
+	}
+
+	// Assumes document/literal wrapped WS-I
+	if len(msg.Parts) == 0 {
+		// Message does not have parts.
+		// This could be a Port with HTTP binding or SOAP 1.2 binding, which are not currently supported.
+		log.Printf("[WARN] %s message doesn't have any parts, ignoring message...", msg.Name)
+		return ""
+	}
+
+	return b.findPartMessageType(msg.Parts[0])
 }
 
-func (b *Builder) isAbstract(t string, checkParent bool) bool {
-	t = stripAliasNSFromType(t)
-	if isBasicType(t) {
-		return true
+// findMessage returns the message a prefixed name refers to, or the only message having its local name when no
+// message has its qualified name.
+func (b *Builder) findMessage(message string) *wsdl.Message {
+	name := b.wsdlScope().resolve(message)
+
+	var found *wsdl.Message
+	count := 0
+	for _, msg := range b.wsdl.Messages {
+		if msg.Name != name.Local {
+			continue
+		}
+
+		if msg.Namespace == name.Space {
+			return msg
+		}
+
+		found = msg
+		count++
 	}
 
-	for _, schema := range b.wsdl.Types.Schemas {
-		for _, complexType := range schema.ComplexTypes {
-			if complexType.Name == t {
-				if checkParent {
-					if complexType.Abstract {
-						return true
-					}
-					baseType := stripAliasNSFromType(complexType.ComplexContent.Extension.Base)
-
-					if baseType == "" {
-						return false
-					}
-
-					for _, complexTypeInner := range schema.ComplexTypes {
-						if complexTypeInner.Name == baseType && complexTypeInner.Abstract {
-							return true
-						}
-					}
-				} else {
-					return complexType.Abstract
-				}
-			}
+	if count == 1 {
+		return found
+	}
+
+	return nil
+}
+
+// portTypeName returns the qualified name of a port type.
+func portTypeName(portType *wsdl.PortType) xml.Name {
+	return xml.Name{Space: portType.Namespace, Local: portType.Name}
+}
+
+// findBindings returns the bindings of a port type, the ones whose type is the qualified name of the port type, or
+// else the ones whose type has its local name when the port type is the only one having it.
+func (b *Builder) findBindings(portType xml.Name) []*wsdl.Binding {
+	var bindings, named []*wsdl.Binding
+	for _, binding := range b.wsdl.Binding {
+		switch name := b.wsdlScope().resolve(binding.Type); {
+		case name == portType:
+			bindings = append(bindings, binding)
+		case name.Local == portType.Local:
+			named = append(named, binding)
 		}
 	}
 
-	return false
+	if len(bindings) > 0 {
+		return bindings
+	}
+
+	for _, other := range b.wsdl.PortTypes {
+		if other.Name == portType.Local && portTypeName(other) != portType {
+			return nil
+		}
+	}
+
+	return named
 }
 
-func (b *Builder) findMessageType(message string) string {
-	message = stripAliasNSFromType(message)
+// findPartMessageType returns the Go type name of a message part, following the element declaration when the part
+// refers to one.
+func (b *Builder) findPartMessageType(part *wsdl.Part) string {
+	if part.Type != "" {
+		return stripPointerFromType(b.symbols.goType(b.wsdlScope(), part.Type, false))
+	}
 
-	for _, msg := range b.wsdl.Messages {
-		if msg.Name != message {
+	element, name, ok := b.symbols.elements.get(b.wsdlScope().resolve(part.Element))
+	if !ok {
+		return b.goName(name.Local)
+	}
+
+	if element.Type != "" {
+		return stripPointerFromType(b.symbols.goType(schemaScope(b.symbols.schemas[element]), element.Type, false))
+	}
+
+	return b.symbols.elementNames[name]
+}
+
+// findNameByType returns the name of the element declared with a type, or the name of the type when there is no
+// such element or several differently named ones.
+func (b *Builder) findNameByType(name xml.Name) string {
+	return b.symbols.findNameByType(name)
+}
+
+func (b *Builder) findSOAPAction(operation string, portType xml.Name) string {
+	_, soapOp := b.findBindingOperation(operation, portType)
+	if soapOp == nil {
+		return ""
+	}
+
+	if b.isSOAP12(portType) {
+		return soapOp.SOAP12Operation.SOAPAction
+	}
+
+	return soapOp.SOAPOperation.SOAPAction
+}
+
+// findBindingOperation returns the binding, and its operation, used to generate the given port type operation.
+// SOAP 1.1 bindings are preferred over SOAP 1.2 ones when the port type is bound by both.
+func (b *Builder) findBindingOperation(operation string, portType xml.Name) (*wsdl.Binding, *wsdl.Operation) {
+	isSOAP12 := b.isSOAP12(portType)
+
+	for _, binding := range b.findBindings(portType) {
+		if binding.IsSOAP12() != isSOAP12 {
 			continue
 		}
 
-		// Assumes document/literal wrapped WS-I
-		if len(msg.Parts) == 0 {
-			// Message does not have parts.
-			// This could be a Port with HTTP binding or SOAP 1.2 binding, which are not currently supported.
-			log.Printf("[WARN] %s message doesn't have any parts, ignoring message...", msg.Name)
-			continue
+		for _, soapOp := range binding.Operations {
+			if soapOp.Name == operation {
+				return binding, soapOp
+			}
 		}
+	}
 
-		part := msg.Parts[0]
-		if part.Type != "" {
-			return stripAliasNSFromType(part.Type)
+	return nil, nil
+}
+
+// isRPC reports whether an operation is bound with style="rpc", either on the operation itself or inherited from the binding.
+func (b *Builder) isRPC(operation string, portType xml.Name) bool {
+	binding, soapOp := b.findBindingOperation(operation, portType)
+	if soapOp == nil {
+		return false
+	}
+
+	style := soapOp.SOAPOperation.Style
+	if b.isSOAP12(portType) {
+		style = soapOp.SOAP12Operation.Style
+	}
+
+	if style == "" {
+		style = binding.SOAPBinding.Style
+		if binding.SOAP12Binding != nil {
+			style = binding.SOAP12Binding.Style
 		}
+	}
 
-		elRef := stripAliasNSFromType(part.Element)
+	return style == "rpc"
+}
 
-		for _, schema := range b.wsdl.Types.Schemas {
-			for _, el := range schema.Elements {
-				if strings.EqualFold(elRef, el.Name) {
-					if el.Type != "" {
-						return stripAliasNSFromType(el.Type)
-					}
+// findRPCNamespace returns the namespace of the RPC wrapper element, as declared by the soap:body of the binding operation.
+func (b *Builder) findRPCNamespace(operation string, portType xml.Name, output bool) string {
+	_, soapOp := b.findBindingOperation(operation, portType)
+	if soapOp == nil {
+		return ""
+	}
 
-					return el.Name
-				}
-			}
+	body := soapOp.Input.SOAPBody
+	if output {
+		body = soapOp.Output.SOAPBody
+	}
+
+	if b.isSOAP12(portType) {
+		body = soapOp.Input.SOAP12Body
+		if output {
+			body = soapOp.Output.SOAP12Body
 		}
 	}
-	return ""
+
+	if body.Namespace != "" {
+		return body.Namespace
+	}
+
+	return b.wsdl.TargetNamespace
 }
 
-func (b *Builder) getNSFromType(ns string) string {
-	aliasNS := getAliasNS(ns)
+// isBodyElements reports whether the body parts of a document style message need a synthesized message type:
+// either there are several of them, or the single one has no generated type carrying its element name.
+func (b *Builder) isBodyElements(parts []*wsdl.Part) bool {
+	if len(parts) != 1 {
+		return len(parts) > 1
+	}
 
-	for _, schema := range b.wsdl.Types.Schemas {
-		for alias, url := range schema.Xmlns {
-			if alias == aliasNS {
-				return url
+	return parts[0].Type != "" || !strings.HasPrefix(b.findPartType(parts[0]), "*")
+}
+
+// findBodyParts returns the parts of an operation message which are carried in the SOAP body, honouring the
+// parts attribute of the soap:body, the other parts being bound to headers or attachments.
+func (b *Builder) findBodyParts(operation string, portType xml.Name, message string, output bool) []*wsdl.Part {
+	parts := b.findMessageParts(message)
+
+	_, soapOp := b.findBindingOperation(operation, portType)
+	if soapOp == nil {
+		return parts
+	}
+
+	selection := soapOp.Input.SOAPBody.Parts
+	if output {
+		selection = soapOp.Output.SOAPBody.Parts
+	}
+
+	if b.isSOAP12(portType) {
+		selection = soapOp.Input.SOAP12Body.Parts
+		if output {
+			selection = soapOp.Output.SOAP12Body.Parts
+		}
+	}
+
+	if strings.TrimSpace(selection) == "" {
+		return parts
+	}
+
+	var selected []*wsdl.Part
+	for _, name := range strings.Fields(selection) {
+		for _, part := range parts {
+			if part.Name == name {
+				selected = append(selected, part)
 			}
 		}
 	}
-	return ""
+
+	return selected
 }
 
-func getAliasNS(typeName string) string {
-	r := strings.Split(typeName, ":")
-	if len(r) == 2 && r[0] != "xs" {
-		return r[0] + ":"
+// findMessageParts returns the parts of a message, each one becoming a child of the RPC wrapper element.
+func (b *Builder) findMessageParts(message string) []*wsdl.Part {
+	if msg := b.findMessage(message); msg != nil {
+		return msg.Parts
 	}
-	return ""
+
+	return nil
+}
+
+// findPartType returns the Go type of a message part, following the element declaration when the part refers to one.
+func (b *Builder) findPartType(part *wsdl.Part) string {
+	if part.Type != "" {
+		return b.symbols.goType(b.wsdlScope(), part.Type, false)
+	}
+
+	element, name, ok := b.symbols.elements.get(b.wsdlScope().resolve(part.Element))
+	if !ok {
+		return "*" + b.goName(name.Local)
+	}
+
+	if element.Type != "" {
+		return b.symbols.goType(schemaScope(b.symbols.schemas[element]), element.Type, element.Nillable)
+	}
+
+	return "*" + b.symbols.elementNames[name]
 }
 
-// Given a type, check if there's an Element with that type, and return its name.
-func (b *Builder) findNameByType(name string) string {
-	return NewXsdParser(nil, b.wsdl.Types.Schemas).findNameByType(name)
+// findPartTag returns the XML tag of a message part: parts declared by type are unqualified accessors named after the part,
+// whereas parts declared by element keep the element qualified name.
+func (b *Builder) findPartTag(part *wsdl.Part) string {
+	name := b.findPartName(part)
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + " " + name.Local
 }
 
-func (b *Builder) findSOAPAction(operation, portType string) string {
+// findPartName returns the qualified name of the body element carrying a message part.
+func (b *Builder) findPartName(part *wsdl.Part) xml.Name {
+	if part.Type != "" {
+		return xml.Name{Local: part.Name}
+	}
+
+	return b.symbols.elementName(b.wsdlScope(), part.Element)
+}
+
+// isSOAP12 reports whether a port type is only bound through SOAP 1.2 bindings,
+// in which case the generated client must talk SOAP 1.2.
+func (b *Builder) isSOAP12(portType xml.Name) bool {
+	found := false
+
+	for _, binding := range b.findBindings(portType) {
+		if !binding.IsSOAP12() {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
+
+// isEncoded reports whether any operation of a port type is bound with use="encoded",
+// in which case the generated client must send SOAP Section 5 encoded messages.
+func (b *Builder) isEncoded(portType xml.Name) bool {
+	for _, binding := range b.findBindings(portType) {
+		if isEncodedBinding(binding) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// usesEncoding reports whether any binding of the document uses SOAP encoding, the generated types then
+// carry their XML Schema type name to write the xsi:type attributes.
+func (b *Builder) usesEncoding() bool {
 	for _, binding := range b.wsdl.Binding {
-		if strings.ToUpper(stripAliasNSFromType(binding.Type)) != strings.ToUpper(portType) {
+		if isEncodedBinding(binding) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEncodedBinding(binding *wsdl.Binding) bool {
+	for _, op := range binding.Operations {
+		for _, body := range []wsdl.SOAPBody{op.Input.SOAPBody, op.Input.SOAP12Body, op.Output.SOAPBody, op.Output.SOAP12Body} {
+			if body.Use == "encoded" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// arrayItemType returns the Go type of the items of a complex type restricting soapenc:Array, or an empty
+// string when the complex type is not a SOAP encoded array.
+func (b *Builder) arrayItemType(scope scope, complexType *xsd.ComplexType) string {
+	restriction := complexType.ComplexContent.Restriction
+	if restriction.Base == "" {
+		return ""
+	}
+
+	// the prefix may be left undeclared by documents relying on the usual soapenc prefix
+	if base := scope.resolve(restriction.Base); base.Local != "Array" || base.Space != soap.XmlNsSoapEnc && base.Space != "" {
+		return ""
+	}
+
+	for _, attr := range restriction.Attributes {
+		if attr.ArrayType != "" {
+			itemType := attr.ArrayType
+			if i := strings.Index(itemType, "["); i >= 0 {
+				itemType = itemType[:i]
+			}
+
+			return b.symbols.goType(scope, itemType, false)
+		}
+	}
+
+	for _, el := range restriction.Elements() {
+		if el.Type != "" {
+			return b.symbols.goType(scope, el.Type, false)
+		}
+	}
+
+	return "string"
+}
+
+// findDerivations maps every named complex type derived by extension or restriction to its base type.
+func (b *Builder) findDerivations() map[xml.Name]xml.Name {
+	derivations := make(map[xml.Name]xml.Name)
+
+	for name, complexType := range b.symbols.complexTypes.components {
+		scope := schemaScope(b.symbols.schemas[complexType])
+
+		base := complexType.ComplexContent.Extension.Base
+		if base == "" && b.arrayItemType(scope, complexType) == "" {
+			base = complexType.ComplexContent.Restriction.Base
+		}
+
+		if base == "" {
 			continue
 		}
 
-		for _, soapOp := range binding.Operations {
-			if soapOp.Name == operation {
-				return soapOp.SOAPOperation.SOAPAction
+		// built-in types such as xs:anyType are not part of the generated hierarchies
+		if baseName := b.symbols.typeName(scope, base); b.symbols.complexTypes.components[baseName] != nil {
+			derivations[name] = baseName
+		}
+	}
+
+	return derivations
+}
+
+// isPolymorphic tells whether a complex type is abstract or has derived types, in which case an element of that
+// type may hold any of its derived types and is generated as an interface.
+func (b *Builder) isPolymorphic(name xml.Name) bool {
+	if polymorphic, ok := b.imported.polymorphic[name]; ok {
+		return polymorphic
+	}
+
+	if b.baseTypes[name] {
+		return true
+	}
+
+	complexType, ok := b.symbols.complexTypes.components[name]
+
+	return ok && complexType.Abstract
+}
+
+// xsiTypeBases returns the Go names of the polymorphic types a complex type can stand for, the type itself included.
+func (b *Builder) xsiTypeBases(name xml.Name) []string {
+	var bases []string
+
+	visited := make(map[xml.Name]bool)
+	for ok := true; ok && !visited[name]; name, ok = b.derivations[name] {
+		visited[name] = true
+		if b.isPolymorphic(name) && !b.imported.types[name] {
+			bases = append(bases, b.goTypeName(name))
+		}
+	}
+
+	return bases
+}
+
+// xsiTypes returns the complex types to be registered with their xsi:type name.
+func (b *Builder) xsiTypes() []*model.Registration {
+	var types []*model.Registration
+
+	for _, schema := range b.wsdl.Types.Schemas {
+		for _, complexType := range schema.ComplexTypes {
+			name := xml.Name{Space: schema.TargetNamespace, Local: complexType.Name}
+			if b.symbols.complexTypes.components[name] == complexType && len(b.xsiTypeBases(name)) > 0 {
+				types = append(types, &model.Registration{Name: name, GoType: b.goTypeName(name)})
 			}
 		}
 	}
 
-	return ""
+	return types
+}
+
+// findSubstitutions maps every global element declaring a substitution group to the head element of that group.
+func (b *Builder) findSubstitutions() map[xml.Name]xml.Name {
+	substitutions := make(map[xml.Name]xml.Name)
+
+	for name, element := range b.symbols.elements.components {
+		if element.SubstitutionGroup != "" {
+			substitutions[name] = b.symbols.elementName(schemaScope(b.symbols.schemas[element]), element.SubstitutionGroup)
+		}
+	}
+
+	return substitutions
+}
+
+// isSubstitutionGroupHead tells whether other elements can substitute for an element, in which case a reference
+// to it is generated as a choice of any member of its substitution group.
+func (b *Builder) isSubstitutionGroupHead(element xml.Name) bool {
+	if head, ok := b.imported.heads[element]; ok {
+		return head
+	}
+
+	return b.headElements[element]
+}
+
+// substitutionGroupHeads returns the Go names of the head elements an element can stand for, the element itself
+// included when it is a head.
+func (b *Builder) substitutionGroupHeads(element xml.Name) []string {
+	var heads []string
+
+	visited := make(map[xml.Name]bool)
+	for ok := true; ok && !visited[element]; element, ok = b.substitutions[element] {
+		visited[element] = true
+		if b.isSubstitutionGroupHead(element) && !b.imported.elements[element] {
+			heads = append(heads, b.goElementName(element))
+		}
+	}
+
+	return heads
+}
+
+// targets returns the set of names a mapping points to.
+func targets(mapping map[xml.Name]xml.Name) map[xml.Name]bool {
+	set := make(map[xml.Name]bool, len(mapping))
+	for _, name := range mapping {
+		set[name] = true
+	}
+
+	return set
 }
 
-func (b *Builder) findServiceAddress(name string) string {
-	for _, service := range b.wsdl.Service {
-		for _, port := range service.Ports {
-			if port.Name == name {
-				return port.SOAPAddress.Location
+// globalElements returns the global elements having a Go type of their own or shared with a complex type, to be
+// registered with their name so that the content of wildcards can be decoded into them.
+func (b *Builder) globalElements(pkg *model.Package) []*model.Registration {
+	var elements []*model.Registration
+
+	declared := make(map[string]bool, len(pkg.Types))
+	for _, t := range pkg.Types {
+		declared[t.Name] = t.Underlying != "interface{}"
+	}
+
+	for _, schema := range b.wsdl.Types.Schemas {
+		for _, element := range schema.Elements {
+			name := xml.Name{Space: schema.TargetNamespace, Local: element.Name}
+			if b.symbols.elements.components[name] == element && !element.Abstract && declared[b.goElementName(name)] {
+				elements = append(elements, &model.Registration{Name: name, GoType: b.goElementName(name)})
 			}
 		}
 	}
 
-	return ""
+	return elements
 }
 
-// replaceReservedWords Go reserved keywords to avoid compilation issues
-func replaceReservedWords(identifier string) string {
-	value := reservedWords[identifier]
-	if value != "" {
-		return value
+// declared returns the registrations of the types which are generated, leaving out the types bindings skip or map
+// to other Go types and the ones imported from the packages of episodes.
+func declared(pkg *model.Package, registrations []*model.Registration) []*model.Registration {
+	var kept []*model.Registration
+	for _, registration := range registrations {
+		if pkg.Type(registration.GoType) != nil {
+			kept = append(kept, registration)
+		}
 	}
 
-	return normalize(identifier)
+	return kept
 }
 
-// replaceAttrReservedWords Go reserved keywords to avoid compilation issues
-func replaceAttrReservedWords(identifier string) string {
-	value := reservedWordsInAttr[identifier]
+// substitutionGroupMembers returns the elements to be registered with their name, abstract heads excluded since
+// they never appear in a document.
+func (b *Builder) substitutionGroupMembers() []*model.Registration {
+	var members []*model.Registration
+
+	for _, schema := range b.wsdl.Types.Schemas {
+		for _, element := range schema.Elements {
+			name := xml.Name{Space: schema.TargetNamespace, Local: element.Name}
+			if b.symbols.elements.components[name] == element && !element.Abstract && len(b.substitutionGroupHeads(name)) > 0 {
+				members = append(members, &model.Registration{Name: name, GoType: b.goElementName(name)})
+			}
+		}
+	}
+
+	return members
+}
+
+// replaceReservedWords Go reserved keywords to avoid compilation issues
+func replaceReservedWords(identifier string) string {
+	value := reservedWords[identifier]
 	if value != "" {
 		return value
 	}
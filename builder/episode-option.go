@@ -0,0 +1,17 @@
+package builder
+
+// WithEpisode is an Option to build the episode of the generated package along with its code, under the "episode"
+// key. The episode records importPath, the path other packages import the generated one from.
+func WithEpisode(importPath string) Option {
+	return func(o *Options) {
+		o.Episode = importPath
+	}
+}
+
+// WithEpisodes is an Option to import the types and elements the episodes read from files list from the packages
+// generated for them, instead of generating them again.
+func WithEpisodes(files ...string) Option {
+	return func(o *Options) {
+		o.Episodes = append(o.Episodes, files...)
+	}
+}
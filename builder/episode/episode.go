@@ -0,0 +1,76 @@
+package episode
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+)
+
+// Episode is the manifest of a generated package: the Go types generated for the types and elements of the
+// namespaces of its schemas. A later build reading it imports these types from the package instead of generating
+// them again, so that the packages of WSDL documents sharing schemas share their types too.
+//
+//	<episode package="common" import="example.com/soap/common">
+//		<namespace uri="http://example.com/common.xsd">
+//			<type name="address" goType="Address"/>
+//			<type name="Shape" goType="Shape" polymorphic="true"/>
+//			<element name="Address" goType="Address"/>
+//		</namespace>
+//	</episode>
+type Episode struct {
+	XMLName    xml.Name     `xml:"episode"`
+	Package    string       `xml:"package,attr"`
+	Import     string       `xml:"import,attr"`
+	Namespaces []*Namespace `xml:"namespace"`
+}
+
+// Namespace lists the Go types generated for the types and elements of a namespace.
+type Namespace struct {
+	URI      string       `xml:"uri,attr"`
+	Types    []*Component `xml:"type"`
+	Elements []*Component `xml:"element"`
+}
+
+// Component is a type or an element along with its Go type. Polymorphic types and heads of substitution groups are
+// held by the wrappers the package derives from their name.
+type Component struct {
+	Name        string `xml:"name,attr"`
+	GoType      string `xml:"goType,attr"`
+	Polymorphic bool   `xml:"polymorphic,attr,omitempty"`
+	Head        bool   `xml:"head,attr,omitempty"`
+}
+
+// Read reads an episode.
+func Read(file string) (*Episode, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(Episode)
+	if err := xml.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Marshal returns the XML document of an episode.
+func (e *Episode) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(e, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// Namespace returns the namespace with the given URI, or nil when the episode has none.
+func (e *Episode) Namespace(uri string) *Namespace {
+	for _, ns := range e.Namespaces {
+		if ns.URI == uri {
+			return ns
+		}
+	}
+
+	return nil
+}
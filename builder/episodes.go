@@ -0,0 +1,147 @@
+package builder
+
+import (
+	"encoding/xml"
+	"errors"
+
+	"github.com/go-aegian/gowsdlsoap/builder/episode"
+	"github.com/go-aegian/gowsdlsoap/builder/model"
+)
+
+// imports holds the types and elements the episodes of previous builds import from their packages, along with the
+// polymorphic types and the heads of substitution groups among them, as their packages tell.
+type imports struct {
+	types       map[xml.Name]bool
+	elements    map[xml.Name]bool
+	polymorphic map[xml.Name]bool
+	heads       map[xml.Name]bool
+	packages    []*model.Import
+}
+
+// importEpisodes names the types and elements of the namespaces the episodes list after the Go types of their
+// packages, qualified by the name the packages are imported as, so that they are referred to instead of being
+// generated again. Components an episode does not list are generated as usual.
+func (b *Builder) importEpisodes() {
+	b.imported = &imports{
+		types:       make(map[xml.Name]bool),
+		elements:    make(map[xml.Name]bool),
+		polymorphic: make(map[xml.Name]bool),
+		heads:       make(map[xml.Name]bool),
+	}
+
+	for _, e := range b.episodes {
+		alias := ""
+		qualify := func(goType string) string {
+			if alias == "" {
+				alias = b.imported.importPackage(e, goType)
+			}
+
+			return alias + "." + goType
+		}
+
+		for _, ns := range e.Namespaces {
+			for _, component := range ns.Types {
+				name := xml.Name{Space: ns.URI, Local: component.Name}
+				if !b.symbols.declaresType(name) || b.imported.types[name] {
+					continue
+				}
+
+				b.symbols.typeNames[name] = qualify(component.GoType)
+				b.imported.types[name], b.imported.polymorphic[name] = true, component.Polymorphic
+			}
+
+			for _, component := range ns.Elements {
+				name := xml.Name{Space: ns.URI, Local: component.Name}
+				if _, ok := b.symbols.elements.components[name]; !ok || b.imported.elements[name] {
+					continue
+				}
+
+				b.symbols.elementNames[name] = qualify(component.GoType)
+				b.imported.elements[name], b.imported.heads[name] = true, component.Head
+			}
+		}
+	}
+}
+
+// importPackage imports the package of an episode, which is given the name of the package unless another package
+// has it, and returns the name it is imported as.
+func (i *imports) importPackage(e *episode.Episode, goType string) string {
+	taken := make(map[string]bool)
+	for alias := range generatedImports {
+		taken[alias] = true
+	}
+
+	for _, imported := range i.packages {
+		if imported.Path == e.Import {
+			return imported.Alias
+		}
+
+		taken[imported.Alias] = true
+	}
+
+	alias := unique(e.Package, taken)
+	i.packages = append(i.packages, &model.Import{Alias: alias, Path: e.Import, Type: alias + "." + goType})
+
+	return alias
+}
+
+// generates tells whether a Go type is generated for a type or an element, which bindings may skip or map to another
+// Go type and episodes may import from another package.
+func (b *Builder) generates(name xml.Name, element bool) bool {
+	if element {
+		return !b.imported.elements[name] && !b.binder.omits(name, true)
+	}
+
+	return !b.imported.types[name] && !b.binder.omits(name, false)
+}
+
+// episode returns the episode of the generated package, listing the types and elements of the schemas it generates
+// a Go type for.
+func (b *Builder) episode(pkg *model.Package) ([]byte, error) {
+	if !b.exported {
+		return nil, errors.New("episode: the types of the package are not exported, no other package can use them")
+	}
+
+	e := &episode.Episode{Package: b.pkg, Import: b.importPath}
+	namespace := func(uri string) *episode.Namespace {
+		ns := e.Namespace(uri)
+		if ns == nil {
+			ns = &episode.Namespace{URI: uri}
+			e.Namespaces = append(e.Namespaces, ns)
+		}
+
+		return ns
+	}
+
+	generated := func(name xml.Name, goType string, element bool) bool {
+		return b.generates(name, element) && pkg.Type(goType) != nil
+	}
+
+	for _, schema := range b.wsdl.Types.Schemas {
+		for _, simpleType := range schema.SimpleType {
+			name := xml.Name{Space: schema.TargetNamespace, Local: simpleType.Name}
+			if b.symbols.simpleTypes.components[name] == simpleType && generated(name, b.goTypeName(name), false) {
+				ns := namespace(name.Space)
+				ns.Types = append(ns.Types, &episode.Component{Name: name.Local, GoType: b.goTypeName(name)})
+			}
+		}
+
+		for _, complexType := range schema.ComplexTypes {
+			name := xml.Name{Space: schema.TargetNamespace, Local: complexType.Name}
+			if b.symbols.complexTypes.components[name] == complexType && generated(name, b.goTypeName(name), false) {
+				ns := namespace(name.Space)
+				ns.Types = append(ns.Types, &episode.Component{Name: name.Local, GoType: b.goTypeName(name), Polymorphic: b.isPolymorphic(name)})
+			}
+		}
+
+		for _, element := range schema.Elements {
+			name := xml.Name{Space: schema.TargetNamespace, Local: element.Name}
+			if b.symbols.elements.components[name] == element && generated(name, b.goElementName(name), true) {
+				ns := namespace(name.Space)
+				ns.Elements = append(ns.Elements, &episode.Component{Name: name.Local, GoType: b.goElementName(name), Head: b.isSubstitutionGroupHead(name)})
+			}
+		}
+	}
+
+	return e.Marshal()
+}
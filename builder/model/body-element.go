@@ -0,0 +1,9 @@
+package model
+
+import "encoding/xml"
+
+// BodyElement is a child of the SOAP body, held by the field of a message type.
+type BodyElement struct {
+	Name  xml.Name
+	Field string
+}
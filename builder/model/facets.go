@@ -0,0 +1,12 @@
+package model
+
+// Facets are the constraining facets of a simple type Validate checks, written as an xsd.Facets.
+type Facets struct {
+	Pattern      string
+	MinInclusive string
+	MaxInclusive string
+	Length       string
+	MinLength    string
+	MaxLength    string
+	Enumeration  []string
+}
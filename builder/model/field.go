@@ -0,0 +1,42 @@
+package model
+
+import "strings"
+
+// Field is a field of a struct. Embedded fields only have a type, fields holding an anonymous struct have their
+// fields in Struct and are repeated when Slice is set.
+type Field struct {
+	Name     string
+	Type     string
+	Tag      string
+	Doc      string
+	Embedded bool
+	Slice    bool
+	Struct   []*Field
+
+	// Required, Occurs and Facets are the constraints the Validate method of the struct checks on the field.
+	Required bool
+	Occurs   *Occurs
+	Facets   *Facets
+
+	// Preset is the default or fixed value the constructor of the struct sets the field to.
+	Preset *Preset
+}
+
+// Selector returns the name the field is selected by, which is the name of its type for embedded fields.
+func (f *Field) Selector() string {
+	if !f.Embedded {
+		return f.Name
+	}
+
+	name := strings.TrimLeft(f.Type, "*")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+// HoldsValidated tells whether the field holds values of generated types, which Validate validates in turn.
+func (f *Field) HoldsValidated() bool {
+	return f.Embedded || holdsValidated(f.Type)
+}
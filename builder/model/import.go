@@ -0,0 +1,9 @@
+package model
+
+// Import is a package the generated code imports under Alias, for the types bindings map schema types to. Type is
+// one of these types, declaring a blank variable of which keeps the import used in every generated file.
+type Import struct {
+	Alias string
+	Path  string
+	Type  string
+}
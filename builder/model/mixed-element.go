@@ -0,0 +1,11 @@
+package model
+
+import "encoding/xml"
+
+// MixedElement is a child element declared by a complex type with mixed content, read into values of Type and
+// returned by the accessor named Accessor when it has one.
+type MixedElement struct {
+	Accessor string
+	Name     xml.Name
+	Type     string
+}
@@ -0,0 +1,7 @@
+package model
+
+// Occurs are the numbers of times a repeated element may occur, Max being negative when it is unbounded.
+type Occurs struct {
+	Min int
+	Max int
+}
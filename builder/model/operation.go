@@ -0,0 +1,23 @@
+package model
+
+// Operation is a method of a service. Request and response are the Go types of the messages, the response being
+// empty for one-way operations, which follow the robust-in-only pattern when they have faults and the in-only one
+// otherwise, a fault being returned as the error either way. OptionalResponse is set on in-optional-out operations,
+// whose response is nil when the service replies with nothing. Types are the message types synthesized for the
+// operation.
+type Operation struct {
+	Name             string
+	Doc              string
+	Action           string
+	Request          string
+	Response         string
+	OptionalResponse bool
+	Faults           []*Fault
+	Types            []*Type
+}
+
+// Fault is a fault an operation may return.
+type Fault struct {
+	Name string
+	Doc  string
+}
@@ -0,0 +1,45 @@
+package model
+
+// Package is the code generated for a WSDL document, resolved from its definitions and schemas before any code is
+// rendered. It is built once and only read afterwards, so the generated files can be rendered concurrently.
+type Package struct {
+	Name                     string
+	Imports                  []*Import
+	Types                    []*Type
+	SubstitutionGroupMembers []*Registration
+	XSITypes                 []*Registration
+	GlobalElements           []*Registration
+	Services                 []*Service
+}
+
+// HasWildcards tells whether a struct of the package holds the content of xs:any wildcards.
+func (p *Package) HasWildcards() bool {
+	for _, t := range p.Types {
+		if hasWildcard(t.Fields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasWildcard(fields []*Field) bool {
+	for _, f := range fields {
+		if f.Type == "[]*xsd.Node" || hasWildcard(f.Struct) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Type returns the type with the given Go name, or nil when there is none.
+func (p *Package) Type(name string) *Type {
+	for _, t := range p.Types {
+		if t.Name == name {
+			return t
+		}
+	}
+
+	return nil
+}
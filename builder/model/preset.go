@@ -0,0 +1,15 @@
+package model
+
+// Preset is the value the constructor of a struct sets a field to, the default or fixed value of its element or
+// attribute. Its lexical form is held by the constant Constant.
+type Preset struct {
+	Constant string
+	Value    string
+	Fixed    bool
+}
+
+// FieldPreset is a field the constructor of a struct presets, Path selecting it from the struct.
+type FieldPreset struct {
+	Path string
+	*Preset
+}
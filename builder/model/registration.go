@@ -0,0 +1,9 @@
+package model
+
+import "encoding/xml"
+
+// Registration is a Go type registered under the XML name it stands for, either an xsi:type or an element name.
+type Registration struct {
+	Name   xml.Name
+	GoType string
+}
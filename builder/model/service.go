@@ -0,0 +1,58 @@
+package model
+
+import "strings"
+
+// Service is the client generated for a port type: an interface, and its implementation calling the operations
+// through a proxy client. UnqualifiedElements is set when the schemas declare elements in no namespace, which the
+// client must take out of the namespace of their parent.
+type Service struct {
+	Name                string
+	Implementation      string
+	SOAP12              bool
+	Encoded             bool
+	UnqualifiedElements bool
+	Operations          []*Operation
+}
+
+// ClientOptions returns the options of the copy of the proxy client the operations of the service are called
+// through, none when they are called through the client itself.
+func (s *Service) ClientOptions() []string {
+	var options []string
+
+	if s.SOAP12 {
+		options = append(options, "proxy.WithSOAP12()")
+	}
+
+	if s.Encoded {
+		options = append(options, "proxy.WithSOAPEncoding()")
+	}
+
+	if s.UnqualifiedElements {
+		options = append(options, "proxy.WithUnqualifiedElements()")
+	}
+
+	return options
+}
+
+// ClientSetup describes what the options of the copy of the proxy client set it up for.
+func (s *Service) ClientSetup() string {
+	var setups []string
+
+	if s.SOAP12 {
+		setups = append(setups, "SOAP 1.2")
+	}
+
+	if s.Encoded {
+		setups = append(setups, "SOAP encoding")
+	}
+
+	if s.UnqualifiedElements {
+		setups = append(setups, "unqualified elements")
+	}
+
+	if len(setups) < 2 {
+		return strings.Join(setups, "")
+	}
+
+	return strings.Join(setups[:len(setups)-1], ", ") + " and " + setups[len(setups)-1]
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"encoding/xml"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Type is a named Go type: a struct when it has no underlying type, a defined type otherwise.
+type Type struct {
+	Name       string
+	Doc        string
+	Underlying string
+	Fields     []*Field
+	Constants  []*Constant
+
+	// XSIType is the name the type writes in its xsi:type attributes, for SOAP encoded services.
+	XSIType *xml.Name
+
+	// Polymorphic types get an interface implemented by their derived types and a wrapper holding any of them.
+	Polymorphic bool
+
+	// SubstitutionGroupHead elements get an interface implemented by their members and a wrapper holding any of them.
+	SubstitutionGroupHead bool
+
+	// Markers are the methods implementing the interfaces of the polymorphic types and substitution groups the
+	// type belongs to.
+	Markers []string
+
+	// ListItem is the type of the items of xsd:list types, which write their items separated by spaces.
+	ListItem string
+
+	// Facets are the constraining facets of simple types, checked by their Validate method.
+	Facets *Facets
+
+	// Union types are structs holding a value of one of their member types, the first one it is valid for when read.
+	Union []*UnionMember
+
+	// Mixed types hold their text and child elements in document order in a Content field, the child elements
+	// being read into values of the type of their declaration in MixedElements.
+	Mixed         bool
+	MixedElements []*MixedElement
+
+	// Constructor is the function creating values of structs having fields with a default or fixed value.
+	Constructor string
+
+	// FixedValue is the constant holding the fixed value of the attributes of the type, written when they are left
+	// empty.
+	FixedValue string
+
+	// ModelGroup types are slices of the occurrences of a repeated model group, which read and write the elements
+	// of the occurrences one by one.
+	ModelGroup bool
+
+	// GroupContent types hold the elements of a struct from its first repeated model group or wildcard on, when the struct has
+	// several repeated groups or wildcards, handing each element to the field, group or wildcard holding it.
+	GroupContent bool
+
+	// BodyElements are the children of the SOAP body a message made of several body elements is written as.
+	BodyElements []*BodyElement
+}
+
+// CanValidate tells whether the type can have a Validate method, which interface types cannot.
+func (t *Type) CanValidate() bool {
+	return t.Underlying != "interface{}"
+}
+
+// ValidatesUnderlying tells whether Validate goes on with the value of the underlying type, which holds values of
+// generated types.
+func (t *Type) ValidatesUnderlying() bool {
+	return t.Underlying != "" && holdsValidated(t.Underlying)
+}
+
+// Presets returns the fields the constructor of a struct sets to their default or fixed value, the ones of the
+// anonymous structs it holds included.
+func (t *Type) Presets() []*FieldPreset {
+	return presets("", t.Fields)
+}
+
+func presets(prefix string, fields []*Field) []*FieldPreset {
+	var list []*FieldPreset
+
+	for _, f := range fields {
+		switch {
+		case f.Embedded:
+		case f.Preset != nil:
+			list = append(list, &FieldPreset{Path: prefix + f.Name, Preset: f.Preset})
+		case f.Type == "" && !f.Slice:
+			list = append(list, presets(prefix+f.Name+".", f.Struct)...)
+		}
+	}
+
+	return list
+}
+
+// IsStruct tells whether the type is declared as a struct.
+func (t *Type) IsStruct() bool {
+	return t.Underlying == ""
+}
+
+// Field returns the field with the given name, or nil when there is none.
+func (t *Type) Field(name string) *Field {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// Constant is a value of an enumeration.
+type Constant struct {
+	Name  string
+	Type  string
+	Value string
+	Doc   string
+}
+
+// holdsValidated tells whether values of a Go type hold values of generated types, which have a Validate method,
+// anonymous structs holding them in their fields.
+func holdsValidated(goType string) bool {
+	goType = BaseType(goType)
+	r, _ := utf8.DecodeRuneInString(goType)
+
+	return goType == "" || !strings.Contains(goType, ".") && unicode.IsUpper(r)
+}
+
+// BaseType returns the type a Go type holds values of, through pointers, slices and the generic types of the xsd
+// package.
+func BaseType(goType string) string {
+	for {
+		switch {
+		case strings.HasPrefix(goType, "*"):
+			goType = goType[1:]
+		case strings.HasPrefix(goType, "[]"):
+			goType = goType[2:]
+		case strings.HasPrefix(goType, "xsd.Nillable[") || strings.HasPrefix(goType, "xsd.ListOf["):
+			goType = goType[strings.Index(goType, "[")+1 : len(goType)-1]
+		default:
+			return goType
+		}
+	}
+}
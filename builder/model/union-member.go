@@ -0,0 +1,11 @@
+package model
+
+// UnionMember is a member type of an xsd:union, its values being held with Type, created by the function called
+// Constructor and read back by the accessor called Name. Enumeration restricts the values of the member, when it
+// declares one.
+type UnionMember struct {
+	Name        string
+	Type        string
+	Constructor string
+	Enumeration []string
+}
@@ -0,0 +1,19 @@
+package builder
+
+// WithNaming is an Option to name the generated Go identifiers with the given strategy.
+func WithNaming(naming Naming) Option {
+	return func(o *Options) {
+		o.Naming = naming
+	}
+}
+
+// WithCamelCase is an Option to generate camel cased identifiers, writing the DefaultInitialisms and the given ones
+// in upper case.
+//
+// Names such as order_id, order-id, order.id and orderId all become OrderID.
+func WithCamelCase(initialisms ...string) Option {
+	return func(o *Options) {
+		o.Naming.CamelCase = true
+		o.Naming.Initialisms = append(append([]string(nil), DefaultInitialisms...), initialisms...)
+	}
+}
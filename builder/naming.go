@@ -0,0 +1,166 @@
+package builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultInitialisms are the words camel cased names write in upper case, as golint wants them.
+var DefaultInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "LHS",
+	"QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID", "URI",
+	"URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// Naming is the strategy turning the names of the schema components and of the WSDL operations into Go identifiers.
+// The zero value keeps the names as they are declared, only dropping the characters Go identifiers cannot hold, and
+// the underscores exported names start with, and writing dots as underscores.
+type Naming struct {
+	// CamelCase joins the words of snake_case, kebab-case and dotted names, and of names written in camel case
+	// already, into CamelCase identifiers.
+	CamelCase bool
+
+	// Initialisms are the words camel cased names write in upper case, such as ID in OrderID.
+	Initialisms []string
+}
+
+// identifier returns the Go identifier of a name, exported ones starting with an upper case letter, the
+// underscores a name starts with being left out of them. Names starting with a digit get a leading X, and Go
+// keywords a trailing underscore.
+func (n Naming) identifier(name string, exported bool) string {
+	if n.CamelCase {
+		name = n.camelCase(name, exported)
+	} else {
+		name = normalize(name)
+	}
+
+	if exported {
+		name = strings.TrimLeft(name, "_")
+	}
+
+	if isDigit(firstRune(name)) {
+		name = "X" + name
+		if !exported {
+			name = "x" + name[1:]
+		}
+	}
+
+	if exported {
+		name = makePublic(name)
+	}
+
+	return replaceReservedWords(name)
+}
+
+// camelCase joins the words of a name, each one starting with an upper case letter but the first one of unexported
+// names, which is kept as declared. Words which are initialisms are written in upper case, and words of digits are
+// kept apart by an underscore.
+func (n Naming) camelCase(name string, exported bool) string {
+	var b strings.Builder
+
+	previous := ""
+	for i, word := range words(name) {
+		if isDigit(lastRune(previous)) && isDigit(firstRune(word)) {
+			b.WriteByte('_')
+		}
+
+		previous = word
+		if i == 0 && !exported {
+			b.WriteString(word)
+			continue
+		}
+
+		b.WriteString(n.capitalize(word))
+	}
+
+	return b.String()
+}
+
+func (n Naming) capitalize(word string) string {
+	for _, initialism := range n.Initialisms {
+		if strings.EqualFold(word, initialism) {
+			return initialism
+		}
+	}
+
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+// words splits a name into its words, which are separated by characters Go identifiers cannot hold, by
+// underscores, or by a change of case: a lower case letter or digit followed by an upper case letter, or the last
+// upper case letter of an acronym followed by a lower case letter as in HTTPServer.
+func words(name string) []string {
+	var list []string
+
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(part)
+		start := 0
+
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := !unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i])
+			acronymEnd := unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if lowerToUpper || acronymEnd {
+				list = append(list, string(runes[start:i]))
+				start = i
+			}
+		}
+
+		list = append(list, string(runes[start:]))
+	}
+
+	return list
+}
+
+func firstRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+
+	return 0
+}
+
+func lastRune(s string) rune {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return 0
+	}
+
+	return runes[len(runes)-1]
+}
+
+func isDigit(r rune) bool {
+	return r != 0 && unicode.IsDigit(r)
+}
+
+// unique returns a name none of the taken names has, nor the names derived from it with the formats of derived,
+// numbering it as needed, and takes them.
+func unique(name string, taken map[string]bool, derived ...string) string {
+	named := name
+	for i := 2; taken[named] || !free(named, taken, derived); i++ {
+		named = name + strconv.Itoa(i)
+	}
+
+	taken[named] = true
+	for _, format := range derived {
+		taken[fmt.Sprintf(format, named)] = true
+	}
+
+	return named
+}
+
+// free tells whether none of the names derived from a name is taken.
+func free(name string, taken map[string]bool, derived []string) bool {
+	for _, format := range derived {
+		if taken[fmt.Sprintf(format, name)] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,17 @@
+package builder
+
+// Options customize the code the builder generates.
+type Options struct {
+	Naming   Naming
+	Bindings string
+
+	// Episode is the import path of the generated package, recorded by its episode, and Episodes the episodes of
+	// the packages to import types from.
+	Episode  string
+	Episodes []string
+}
+
+// Option customizes the code the builder generates.
+type Option func(*Options)
+
+var DefaultOptions = Options{}
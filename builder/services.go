@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/go-aegian/gowsdlsoap/builder/model"
+	"github.com/go-aegian/gowsdlsoap/builder/wsdl"
+)
+
+// serviceNames and operationNames are the names the generated code derives from the name of a service and from the
+// name of an operation.
+var (
+	serviceNames   = []string{"New%s"}
+	operationNames = []string{"%sContext"}
+)
+
+// services lowers the port types into the services of the model. The methods of the operations of a port type are
+// numbered when they share their name, as overloaded operations do, unless bindings name them.
+func (b *Builder) services() ([]*model.Service, error) {
+	var services []*model.Service
+
+	for _, portType := range b.wsdl.PortTypes {
+		qualifiedName := portTypeName(portType)
+		name := b.symbols.claim(b.goName(portType.Name), qualifiedName, serviceNames...)
+
+		service := &model.Service{
+			Name:           name,
+			Implementation: b.symbols.reserve(makePrivate(name)),
+			SOAP12:         b.isSOAP12(qualifiedName),
+			Encoded:        b.isEncoded(qualifiedName),
+		}
+
+		methods := make(map[string]bool)
+		for _, op := range portType.Operations {
+			operation := b.operation(op, qualifiedName)
+			if name, ok := b.binder.operationName(portType.Name, op.Name); ok {
+				operation.Name = name
+			}
+
+			operation.Name = unique(operation.Name, methods, operationNames...)
+			service.Operations = append(service.Operations, operation)
+		}
+
+		services = append(services, service)
+	}
+
+	for _, service := range services {
+		service.UnqualifiedElements = b.unqualifiedElements && !service.Encoded
+	}
+
+	return services, b.binder.bindServices(services)
+}
+
+// operation lowers an operation of a port type, synthesizing the message types RPC style and multi-part bare
+// operations need.
+func (b *Builder) operation(op *wsdl.Operation, portType xml.Name) *model.Operation {
+	operation := &model.Operation{
+		Name:    b.goName(op.Name),
+		Doc:     op.Doc,
+		Request: b.findMessageType(op.Input.Message),
+	}
+
+	for _, fault := range op.Faults {
+		operation.Faults = append(operation.Faults, &model.Fault{Name: fault.Name, Doc: fault.Doc})
+	}
+
+	if op.Output.Message != "" {
+		operation.Response = b.findMessageType(op.Output.Message)
+		operation.OptionalResponse = op.OptionalOutput
+	}
+
+	requestParts := b.findBodyParts(op.Name, portType, op.Input.Message, false)
+	responseParts := b.findBodyParts(op.Name, portType, op.Output.Message, true)
+
+	if b.isRPC(op.Name, portType) {
+		operation.Request = b.symbols.reserve(b.goName(op.Name))
+		operation.Types = append(operation.Types, b.rpcWrapper(operation.Request, op.Name, b.findRPCNamespace(op.Name, portType, false), requestParts))
+
+		if operation.Response != "" {
+			operation.Response = b.symbols.reserve(operation.Request + "Response")
+			operation.Types = append(operation.Types, b.rpcWrapper(operation.Response, op.Name+"Response", b.findRPCNamespace(op.Name, portType, true), responseParts))
+		}
+	} else {
+		switch {
+		case b.isBodyElements(requestParts):
+			operation.Request = b.symbols.reserve(b.goName(b.wsdlScope().resolve(op.Input.Message).Local))
+			operation.Types = append(operation.Types, b.bodyElements(operation.Request, requestParts))
+		case len(requestParts) == 1:
+			operation.Request = b.findPartMessageType(requestParts[0])
+		}
+
+		switch {
+		case operation.Response == "":
+		case b.isBodyElements(responseParts):
+			operation.Response = b.symbols.reserve(b.goName(b.wsdlScope().resolve(op.Output.Message).Local))
+			operation.Types = append(operation.Types, b.bodyElements(operation.Response, responseParts))
+		case len(responseParts) == 1:
+			operation.Response = b.findPartMessageType(responseParts[0])
+		}
+	}
+
+	operation.Action = b.findSOAPAction(op.Name, portType)
+	if operation.Action == "" && !b.isSOAP12(portType) {
+		operation.Action = "''"
+	}
+
+	return operation
+}
+
+// rpcWrapper returns the wrapper element synthesized for an RPC style operation message.
+func (b *Builder) rpcWrapper(typeName, element, namespace string, parts []*wsdl.Part) *model.Type {
+	t := &model.Type{Name: typeName}
+	t.Fields = append(t.Fields, xmlNameField(fmt.Sprintf("%s %s", namespace, element)))
+	t.Fields = append(t.Fields, b.partFields(typeName, parts)...)
+
+	// the accessors of the parts bound to types are in no namespace
+	for _, part := range parts {
+		b.unqualifiedElements = b.unqualifiedElements || part.Type != "" && namespace != ""
+	}
+
+	return t
+}
+
+// bodyElements returns the message synthesized for a document/literal bare operation with several body parts.
+func (b *Builder) bodyElements(typeName string, parts []*wsdl.Part) *model.Type {
+	t := &model.Type{Name: typeName, Fields: b.partFields(typeName, parts)}
+
+	for i, part := range parts {
+		t.BodyElements = append(t.BodyElements, &model.BodyElement{Name: b.findPartName(part), Field: t.Fields[i].Name})
+	}
+
+	return t
+}
+
+func (b *Builder) partFields(typeName string, parts []*wsdl.Part) []*model.Field {
+	var fields []*model.Field
+
+	for _, part := range parts {
+		fields = append(fields, &model.Field{
+			Name: b.fieldName(part.Name),
+			Type: b.findPartType(part),
+			Tag:  tag(b.findPartTag(part), part.Name),
+		})
+	}
+
+	nameFields(typeName, fields)
+
+	return fields
+}
@@ -0,0 +1,27 @@
+package soap
+
+import "encoding/xml"
+
+// BodyElements is implemented by messages made of several body elements, as the messages of document/literal
+// bare operations with more than one part are. Each element is written and read as a direct child of the SOAP body.
+type BodyElements interface {
+	BodyElements() []BodyElement
+}
+
+// BodyElement is one of the children of the SOAP body, Value points to the field holding its content.
+type BodyElement struct {
+	Name  xml.Name
+	Value interface{}
+}
+
+// findBodyElement returns the element of the message not read yet whose qualified name is the one of a child of the
+// SOAP body, or -1 when there is none.
+func findBodyElement(elements []BodyElement, decoded []bool, name xml.Name) int {
+	for i, element := range elements {
+		if !decoded[i] && element.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -6,24 +6,41 @@ type BodyResponse struct {
 	XMLName xml.Name    `xml:"Body"`
 	Content interface{} `xml:",omitempty"`
 	Fault   *Fault      `xml:",omitempty"`
+	Fault12 *Fault12    `xml:",omitempty"`
 	faulted bool
 }
 
 // UnmarshalXML of the body xml
 func (b *BodyResponse) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	// an optional response is read into its content, which it records the reading of
+	optional, _ := b.Content.(*OptionalResponse)
+	if optional != nil {
+		b.Content = optional.Content
+	}
+
 	if b.Content == nil {
 		return xml.UnmarshalError("Content must be a pointer to a struct")
 	}
 	if b.Fault == nil {
 		b.Fault = &Fault{Detail: nil}
 	}
+	if b.Fault12 == nil {
+		b.Fault12 = &Fault12{Detail: b.Fault.Detail}
+	}
 
 	var (
 		token    xml.Token
 		err      error
 		consumed bool
+		elements []BodyElement
+		decoded  []bool
 	)
 
+	if message, ok := b.Content.(BodyElements); ok {
+		elements = message.BodyElements()
+		decoded = make([]bool, len(elements))
+	}
+
 Loop:
 	for {
 		if token, err = d.Token(); err != nil {
@@ -36,18 +53,49 @@ Loop:
 
 		switch se := token.(type) {
 		case xml.StartElement:
-			if consumed {
+			if consumed && elements == nil {
 				return xml.UnmarshalError("Found multiple elements inside SOAP body; not wrapped-document/literal WS-I compliant")
 			}
-			if se.Name.Space == "http://schemas.xmlsoap.org/soap/envelope/" && se.Name.Local == "Fault" {
+			if se.Name.Space == XmlNsSoapEnv && se.Name.Local == "Fault" {
 				b.Content = nil
 				b.faulted = true
+				b.Fault12 = nil
 
 				err = d.DecodeElement(b.Fault, &se)
 				if err != nil {
 					return err
 				}
 
+				consumed = true
+			} else if se.Name.Space == XmlNsSoap12Env && se.Name.Local == "Fault" {
+				b.Content = nil
+				b.faulted = true
+				b.Fault = nil
+
+				err = d.DecodeElement(b.Fault12, &se)
+				if err != nil {
+					return err
+				}
+
+				consumed = true
+			} else if elements != nil {
+				i := findBodyElement(elements, decoded, se.Name)
+				if i < 0 {
+					return xml.UnmarshalError("Found unexpected element {" + se.Name.Space + "}" + se.Name.Local + " inside SOAP body")
+				}
+
+				if err = d.DecodeElement(elements[i].Value, &se); err != nil {
+					return err
+				}
+
+				decoded[i] = true
+				consumed = true
+			} else if _, ok := b.Content.(*OneWay); ok {
+				// a one way operation expects nothing but a fault
+				if err = d.Skip(); err != nil {
+					return err
+				}
+
 				consumed = true
 			} else {
 				if err = d.DecodeElement(b.Content, &se); err != nil {
@@ -61,13 +109,21 @@ Loop:
 		}
 	}
 
+	if optional != nil {
+		optional.Received = consumed && !b.faulted
+	}
+
 	return nil
 }
 
 func (b *BodyResponse) ErrorFromFault() error {
 	if b.faulted {
+		if b.Fault12 != nil {
+			return b.Fault12
+		}
 		return b.Fault
 	}
 	b.Fault = nil
+	b.Fault12 = nil
 	return nil
 }
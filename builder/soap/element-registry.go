@@ -0,0 +1,86 @@
+package soap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+
+	"github.com/go-aegian/gowsdlsoap/builder/xsd"
+)
+
+// ElementRegistry maps the names of the elements of substitution groups to the Go types generated for them, so
+// that any member of a group can be sent and received where the head element is declared. It maps as well the
+// names of the global elements, which the content of wildcards is decoded into.
+type ElementRegistry struct {
+	elements map[xml.Name]reflect.Type
+	names    map[reflect.Type]xml.Name
+}
+
+func NewElementRegistry() *ElementRegistry {
+	return &ElementRegistry{elements: make(map[xml.Name]reflect.Type), names: make(map[reflect.Type]xml.Name)}
+}
+
+// Register maps an element name to a Go type, given as a nil pointer to it such as (*FieldURI)(nil).
+func (r *ElementRegistry) Register(name xml.Name, v interface{}) {
+	t := reflect.TypeOf(v)
+	r.elements[name] = t
+	if _, ok := r.names[t]; !ok {
+		r.names[t] = name
+	}
+}
+
+// Marshal writes v as the element name, or as the element its type is registered for when name is empty.
+func (r *ElementRegistry) Marshal(e *xml.Encoder, name xml.Name, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() || value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil
+	}
+
+	if name.Local == "" {
+		registered, ok := r.names[value.Type()]
+		if !ok {
+			return fmt.Errorf("no element is registered for %s", value.Type())
+		}
+		name = registered
+	}
+
+	return e.EncodeElement(v, xml.StartElement{Name: name})
+}
+
+// Unmarshal decodes the element start into a new value of the type registered for its name and stores it into the
+// interface pointed to by v. Elements that are not registered are skipped, leaving v untouched.
+func (r *ElementRegistry) Unmarshal(d *xml.Decoder, start xml.StartElement, v interface{}) error {
+	t, ok := r.elements[start.Name]
+	if !ok {
+		return d.Skip()
+	}
+
+	target := reflect.ValueOf(v).Elem()
+	if !t.AssignableTo(target.Type()) {
+		return fmt.Errorf("element %s is not a %s", start.Name.Local, target.Type())
+	}
+
+	value := reflect.New(t.Elem())
+	if err := d.DecodeElement(value.Interface(), &start); err != nil {
+		return err
+	}
+
+	target.Set(value)
+	return nil
+}
+
+// Decode reads a node, such as the content of a wildcard, into a new value of the type registered for its name,
+// returned as a pointer.
+func (r *ElementRegistry) Decode(n *xsd.Node) (interface{}, error) {
+	t, ok := r.elements[n.XMLName]
+	if !ok {
+		return nil, fmt.Errorf("no type is registered for element %s", n.XMLName.Local)
+	}
+
+	value := reflect.New(t.Elem())
+	if err := n.Decode(value.Interface()); err != nil {
+		return nil, err
+	}
+
+	return value.Interface(), nil
+}
@@ -14,3 +14,8 @@ type Envelope struct {
 func NewEnvelope() *Envelope {
 	return &Envelope{XMLNS: XmlNsSoapEnv, XMLNSXsd: XmlNsSoapXsd, XMLNSXsi: XmlNsSoapXsi}
 }
+
+// NewEnvelope12 creates an envelope using the SOAP 1.2 namespace.
+func NewEnvelope12() *Envelope {
+	return &Envelope{XMLNS: XmlNsSoap12Env, XMLNSXsd: XmlNsSoapXsd, XMLNSXsi: XmlNsSoapXsi}
+}
@@ -0,0 +1,60 @@
+package soap
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Fault12 represents a SOAP 1.2 fault.
+type Fault12 struct {
+	XMLName xml.Name      `xml:"http://www.w3.org/2003/05/soap-envelope Fault"`
+	Code    Fault12Code   `xml:"http://www.w3.org/2003/05/soap-envelope Code"`
+	Reason  []Fault12Text `xml:"http://www.w3.org/2003/05/soap-envelope Reason>Text"`
+	Node    string        `xml:"http://www.w3.org/2003/05/soap-envelope Node,omitempty"`
+	Role    string        `xml:"http://www.w3.org/2003/05/soap-envelope Role,omitempty"`
+	Detail  FaultError    `xml:"http://www.w3.org/2003/05/soap-envelope Detail,omitempty"`
+}
+
+// Fault12Code holds the fault code value and its optional chain of subcodes.
+type Fault12Code struct {
+	Value   string       `xml:"http://www.w3.org/2003/05/soap-envelope Value"`
+	Subcode *Fault12Code `xml:"http://www.w3.org/2003/05/soap-envelope Subcode,omitempty"`
+}
+
+// Fault12Text is a human readable fault reason in a given language.
+type Fault12Text struct {
+	Lang  string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// Subcodes returns the subcode values from the outermost to the innermost one.
+func (c Fault12Code) Subcodes() []string {
+	var codes []string
+	for sc := c.Subcode; sc != nil; sc = sc.Subcode {
+		codes = append(codes, sc.Value)
+	}
+
+	return codes
+}
+
+// ReasonText returns the reason text, preferring the given language when available.
+func (f *Fault12) ReasonText(lang string) string {
+	if len(f.Reason) == 0 {
+		return ""
+	}
+
+	for _, text := range f.Reason {
+		if strings.EqualFold(text.Lang, lang) {
+			return text.Value
+		}
+	}
+
+	return f.Reason[0].Value
+}
+
+func (f *Fault12) Error() string {
+	if f.Detail != nil && f.Detail.HasData() {
+		return f.Detail.ErrorString()
+	}
+	return f.ReasonText("en")
+}
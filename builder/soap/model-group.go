@@ -0,0 +1,212 @@
+package soap
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+var unmarshalerType = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+
+// groupField is a field of the struct holding an occurrence of a repeated model group. The occurrences of a nested
+// repeated group are held by a field whose type reads them, group being the struct holding one of them.
+type groupField struct {
+	index    int
+	name     xml.Name
+	wildcard bool
+	group    reflect.Type
+}
+
+// MarshalModelGroup writes the occurrences of a repeated model group, given as a slice of pointers to the structs
+// holding the elements of an occurrence, the elements of each occurrence in the order of the fields.
+func MarshalModelGroup(e *xml.Encoder, occurrences interface{}) error {
+	values := reflect.ValueOf(occurrences)
+
+	for i := 0; i < values.Len(); i++ {
+		if err := marshalOccurrence(e, reflect.Indirect(values.Index(i))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalGroupContent writes the elements held by the struct pointed to by content in the order of its fields. The
+// struct holds the elements of another struct from its first repeated model group or wildcard on.
+func MarshalGroupContent(e *xml.Encoder, content interface{}) error {
+	return marshalOccurrence(e, reflect.Indirect(reflect.ValueOf(content)))
+}
+
+func marshalOccurrence(e *xml.Encoder, occurrence reflect.Value) error {
+	if !occurrence.IsValid() {
+		return nil
+	}
+
+	for _, field := range groupFields(occurrence.Type()) {
+		value := occurrence.Field(field.index)
+		if value.IsZero() {
+			continue
+		}
+
+		if err := e.EncodeElement(value.Interface(), xml.StartElement{Name: field.name}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalModelGroup decodes the element start into the occurrences of a repeated model group pointed to by v.
+// The element belongs to the last occurrence when one of its fields coming after the last element read holds it,
+// and otherwise starts a new occurrence. Elements that are not part of the group are skipped.
+func UnmarshalModelGroup(d *xml.Decoder, start xml.StartElement, v interface{}) error {
+	occurrences := reflect.ValueOf(v).Elem()
+	occurrenceType := occurrences.Type().Elem().Elem()
+	fields := groupFields(occurrenceType)
+
+	var occurrence reflect.Value
+	i := -1
+	if n := occurrences.Len(); n > 0 {
+		last := occurrences.Index(n - 1).Elem()
+		if i = matchGroupField(fields, start.Name, lastGroupField(last, fields)); i >= 0 && !startsOccurrence(last, fields, i) {
+			occurrence = last
+		}
+	}
+
+	if !occurrence.IsValid() {
+		if i = matchGroupField(fields, start.Name, 0); i < 0 {
+			return d.Skip()
+		}
+
+		value := reflect.New(occurrenceType)
+		occurrences.Set(reflect.Append(occurrences, value))
+		occurrence = value.Elem()
+	}
+
+	return d.DecodeElement(occurrence.Field(fields[i].index).Addr().Interface(), &start)
+}
+
+// UnmarshalGroupContent decodes the element start into the field of the struct pointed to by content holding it,
+// preferably one coming after the last element read. Elements the struct does not hold are skipped.
+func UnmarshalGroupContent(d *xml.Decoder, start xml.StartElement, content interface{}) error {
+	value := reflect.ValueOf(content).Elem()
+	fields := groupFields(value.Type())
+
+	i := matchGroupField(fields, start.Name, lastGroupField(value, fields))
+	if i < 0 {
+		i = matchGroupField(fields, start.Name, 0)
+	}
+
+	if i < 0 {
+		return d.Skip()
+	}
+
+	return d.DecodeElement(value.Field(fields[i].index).Addr().Interface(), &start)
+}
+
+// groupFields returns the element fields of a struct, named as encoding/xml names them.
+func groupFields(t reflect.Type) []groupField {
+	var fields []groupField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Name == "XMLName" {
+			continue
+		}
+
+		tag := strings.Split(f.Tag.Get("xml"), ",")
+		if tag[0] == "-" || containsFlag(tag[1:], "attr") || containsFlag(tag[1:], "chardata") {
+			continue
+		}
+
+		field := groupField{index: i, name: xml.Name{Local: f.Name}, wildcard: containsFlag(tag[1:], "any")}
+		if tag[0] != "" {
+			if j := strings.LastIndex(tag[0], " "); j >= 0 {
+				field.name = xml.Name{Space: tag[0][:j], Local: tag[0][j+1:]}
+			} else {
+				field.name = xml.Name{Local: tag[0]}
+			}
+		}
+
+		if field.wildcard && f.Type.Kind() == reflect.Slice && reflect.PtrTo(f.Type).Implements(unmarshalerType) {
+			if occurrence := f.Type.Elem(); occurrence.Kind() == reflect.Ptr && occurrence.Elem().Kind() == reflect.Struct {
+				field.wildcard, field.group = false, occurrence.Elem()
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// holds tells whether an element is read into the field, named after it or holding the nested group it is part of.
+func (f groupField) holds(name xml.Name) bool {
+	if f.group == nil {
+		return !f.wildcard && f.name.Local == name.Local && (f.name.Space == "" || f.name.Space == name.Space)
+	}
+
+	for _, field := range groupFields(f.group) {
+		if field.holds(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchGroupField returns the index of the field an element is read into among the fields from the field from on:
+// the first field holding it, or else the first wildcard field. It returns -1 when there is none.
+func matchGroupField(fields []groupField, name xml.Name, from int) int {
+	wildcard := -1
+
+	for i := from; i < len(fields); i++ {
+		switch {
+		case fields[i].wildcard:
+			if wildcard < 0 {
+				wildcard = i
+			}
+		case fields[i].holds(name):
+			return i
+		}
+	}
+
+	return wildcard
+}
+
+// lastGroupField returns the index of the last field of an occurrence holding an element, or 0 when there is none.
+func lastGroupField(occurrence reflect.Value, fields []groupField) int {
+	for i := len(fields) - 1; i > 0; i-- {
+		if !occurrence.Field(fields[i].index).IsZero() {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// startsOccurrence tells whether an element read into the field i starts a new occurrence after the last one.
+func startsOccurrence(last reflect.Value, fields []groupField, i int) bool {
+	for j := i; j < len(fields); j++ {
+		value := last.Field(fields[j].index)
+		if value.IsZero() {
+			continue
+		}
+
+		if j > i || value.Kind() != reflect.Slice {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,5 @@
+package soap
+
+// OneWay is the response of an operation without output message, following either the in-only or the
+// robust-in-only message exchange pattern: the body of the response, if any, holds nothing but a fault.
+type OneWay struct{}
@@ -0,0 +1,9 @@
+package soap
+
+// OptionalResponse is the response of an operation following the in-optional-out message exchange pattern: the
+// body of the response holds the output message read into Content, a fault, or nothing. Received tells whether the
+// output message was read.
+type OptionalResponse struct {
+	Content  interface{}
+	Received bool
+}
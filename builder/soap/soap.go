@@ -4,6 +4,12 @@ const (
 	XmlNsSoapXsi                  = "http://www.w3.org/2001/XMLSchema-instance"
 	XmlNsSoapXsd                  = "http://www.w3.org/2001/XMLSchema"
 	XmlNsSoapEnv                  = "http://schemas.xmlsoap.org/soap/envelope/"
+	XmlNsSoap12Env                = "http://www.w3.org/2003/05/soap-envelope"
+	XmlNsSoapEnc                  = "http://schemas.xmlsoap.org/soap/encoding/"
+	XmlNsSoap12Enc                = "http://www.w3.org/2003/05/soap-encoding"
+	XmlNsXml                      = "http://www.w3.org/XML/1998/namespace"
+	SoapContentType               = `text/xml; charset="utf-8"`
+	Soap12ContentType             = `application/soap+xml; charset="utf-8"`
 	MtomContentType               = `multipart/related; start-info="application/soap+xml"; type="application/xop+xml"; boundary="%s"`
 	ContentTypeHeader             = "Content-Type"
 	ContentTransferEncodingHeader = "Content-Transfer-Encoding"
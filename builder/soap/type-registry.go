@@ -0,0 +1,164 @@
+package soap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// TypeRegistry maps the xsi:type names of complex types to the Go types generated for them, so that a value of a
+// derived type can be sent and received where its base type is declared. Each generated package has its own.
+type TypeRegistry struct {
+	types    map[xml.Name]reflect.Type
+	names    map[reflect.Type]xml.Name
+	prefixes uint64
+}
+
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{types: make(map[xml.Name]reflect.Type), names: make(map[reflect.Type]xml.Name)}
+}
+
+// Register maps an xsi:type name to a Go type, given as a nil pointer to it such as (*ItemType)(nil).
+func (r *TypeRegistry) Register(name xml.Name, v interface{}) {
+	t := reflect.TypeOf(v)
+	r.types[name] = t
+	r.names[t] = name
+}
+
+// Name returns the xsi:type name of the type of v.
+func (r *TypeRegistry) Name(v interface{}) (xml.Name, bool) {
+	name, ok := r.names[reflect.TypeOf(v)]
+	return name, ok
+}
+
+// Type returns the Go type registered for an xsi:type name. When the namespace is unknown the type is looked up
+// by its local name, provided it is not ambiguous.
+func (r *TypeRegistry) Type(name xml.Name) (reflect.Type, bool) {
+	if t, ok := r.types[name]; ok || name.Space != "" {
+		return t, ok
+	}
+
+	var found reflect.Type
+	for n, t := range r.types {
+		if n.Local == name.Local {
+			if found != nil {
+				return nil, false
+			}
+			found = t
+		}
+	}
+
+	return found, found != nil
+}
+
+// Marshal writes v as the element start, along with its xsi:type when its type is not base.
+func (r *TypeRegistry) Marshal(e *xml.Encoder, start xml.StartElement, v interface{}, base interface{}) error {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() || value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil
+	}
+
+	if name, ok := r.Name(v); ok && value.Type() != reflect.TypeOf(base) {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: XmlNsSoapXsi})
+		start.Attr = append(start.Attr, r.typeAttrs(start, name)...)
+	}
+
+	return e.EncodeElement(v, start)
+}
+
+// typeAttrs returns the xsi:type attribute naming a type on the element start. The name is unprefixed when its
+// namespace is the one encoding/xml declares as default on the element, and otherwise uses a prefix declared on
+// the element and never used before by the registry, so that it can't shadow a prefix its content relies on.
+func (r *TypeRegistry) typeAttrs(start xml.StartElement, name xml.Name) []xml.Attr {
+	if name.Space == "" || name.Space == start.Name.Space {
+		return []xml.Attr{{Name: xml.Name{Local: "xsi:type"}, Value: name.Local}}
+	}
+
+	prefix := fmt.Sprintf("xt%d", atomic.AddUint64(&r.prefixes, 1))
+
+	return []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:" + prefix}, Value: name.Space},
+		{Name: xml.Name{Local: "xsi:type"}, Value: prefix + ":" + name.Local},
+	}
+}
+
+// Unmarshal decodes the element start into a new value of the type named by its xsi:type, base being used when
+// the element has no xsi:type or an unknown one, and stores it into the interface pointed to by v.
+func (r *TypeRegistry) Unmarshal(d *xml.Decoder, start xml.StartElement, v interface{}, base interface{}) error {
+	target := reflect.ValueOf(v).Elem()
+	t := reflect.TypeOf(base)
+
+	if name, ok := xsiType(start); ok {
+		if derived, found := r.Type(name); found {
+			t = derived
+		}
+	}
+
+	if !t.AssignableTo(target.Type()) {
+		return fmt.Errorf("xsi:type %s of element %s is not a %s", r.names[t].Local, start.Name.Local, target.Type())
+	}
+
+	// the element is named after the base type, not after the type it is decoded into
+	if name, ok := elementName(t.Elem()); ok {
+		start.Name = name
+	}
+
+	value := reflect.New(t.Elem())
+	if err := d.DecodeElement(value.Interface(), &start); err != nil {
+		return err
+	}
+
+	target.Set(value)
+	return nil
+}
+
+// xsiType returns the name given by the xsi:type attribute of an element, resolving its prefix against the
+// namespaces declared on the element itself. The namespace stays empty when the prefix is declared elsewhere.
+func xsiType(start xml.StartElement) (xml.Name, bool) {
+	for _, attr := range start.Attr {
+		if attr.Name.Local != "type" || attr.Name.Space != XmlNsSoapXsi && attr.Name.Space != "xsi" {
+			continue
+		}
+
+		prefix, local := "", attr.Value
+		if i := strings.Index(attr.Value, ":"); i >= 0 {
+			prefix, local = attr.Value[:i], attr.Value[i+1:]
+		}
+
+		name := xml.Name{Local: local}
+		for _, ns := range start.Attr {
+			if prefix != "" && ns.Name.Space == "xmlns" && ns.Name.Local == prefix || prefix == "" && ns.Name.Space == "" && ns.Name.Local == "xmlns" {
+				name.Space = ns.Value
+			}
+		}
+
+		return name, true
+	}
+
+	return xml.Name{}, false
+}
+
+// elementName returns the element name a struct is bound to by its XMLName field.
+func elementName(t reflect.Type) (xml.Name, bool) {
+	if t.Kind() != reflect.Struct {
+		return xml.Name{}, false
+	}
+
+	field, ok := t.FieldByName("XMLName")
+	if !ok || field.Type != reflect.TypeOf(xml.Name{}) {
+		return xml.Name{}, false
+	}
+
+	tag := strings.Split(field.Tag.Get("xml"), ",")[0]
+	if tag == "" {
+		return xml.Name{}, false
+	}
+
+	if i := strings.LastIndex(tag, " "); i >= 0 {
+		return xml.Name{Space: tag[:i], Local: tag[i+1:]}, true
+	}
+
+	return xml.Name{Local: tag}, true
+}
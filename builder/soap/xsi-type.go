@@ -0,0 +1,9 @@
+package soap
+
+import "encoding/xml"
+
+// XSITyper is implemented by generated types which know their XML Schema type name.
+// It is used to write the xsi:type attribute of SOAP encoded values.
+type XSITyper interface {
+	XSIType() xml.Name
+}
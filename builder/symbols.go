@@ -0,0 +1,447 @@
+package builder
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
+	"github.com/go-aegian/gowsdlsoap/builder/xsd"
+)
+
+// index maps the qualified names of one kind of schema component to the components.
+type index[T any] struct {
+	components map[xml.Name]T
+	locals     map[string][]xml.Name
+}
+
+func newIndex[T any]() *index[T] {
+	return &index[T]{components: make(map[xml.Name]T), locals: make(map[string][]xml.Name)}
+}
+
+// add indexes a component, the first declaration of a name winning over the later ones.
+func (i *index[T]) add(name xml.Name, component T) bool {
+	if _, ok := i.components[name]; ok {
+		return false
+	}
+
+	i.components[name] = component
+	i.locals[name.Local] = append(i.locals[name.Local], name)
+
+	return true
+}
+
+// get returns the component of a qualified name. When no component has that name, which happens with chameleon
+// schemas or undeclared prefixes, the local name is used provided a single component has it.
+func (i *index[T]) get(name xml.Name) (T, xml.Name, bool) {
+	if component, ok := i.components[name]; ok {
+		return component, name, true
+	}
+
+	if names := i.locals[name.Local]; len(names) == 1 {
+		return i.components[names[0]], names[0], true
+	}
+
+	var none T
+	return none, xml.Name{}, false
+}
+
+// symbols indexes the global components of all the schemas by qualified name, along with the schema declaring each
+// of them, so that references resolve in constant time whatever the prefixes in use. It also allots the Go name of
+// every type and element, and reserves the other names declared by the generated code, the ones taken already being
+// numbered.
+type symbols struct {
+	elements        *index[*xsd.Element]
+	complexTypes    *index[*xsd.ComplexType]
+	simpleTypes     *index[*xsd.SimpleType]
+	attributes      *index[*xsd.Attribute]
+	groups          *index[*xsd.Group]
+	attributeGroups *index[*xsd.AttributeGroup]
+	schemas         map[interface{}]*xsd.Schema
+	typeNames       map[xml.Name]string
+	elementNames    map[xml.Name]string
+	elementsByType  map[xml.Name][]string
+	goNames         map[string]bool
+
+	// goTypes and elementGoTypes are the Go types bindings map types and elements to, which are not generated
+	goTypes        map[xml.Name]string
+	elementGoTypes map[xml.Name]string
+}
+
+// reservedGoNames are the names declared by the header of the generated code and the registries of the types.
+var reservedGoNames = []string{"AnyType", "AnyURI", "NCName", "SubstitutionGroups", "XSITypes", "GlobalElements"}
+
+func newSymbols(schemas []*xsd.Schema) *symbols {
+	s := &symbols{
+		elements:        newIndex[*xsd.Element](),
+		complexTypes:    newIndex[*xsd.ComplexType](),
+		simpleTypes:     newIndex[*xsd.SimpleType](),
+		attributes:      newIndex[*xsd.Attribute](),
+		groups:          newIndex[*xsd.Group](),
+		attributeGroups: newIndex[*xsd.AttributeGroup](),
+		schemas:         make(map[interface{}]*xsd.Schema),
+		typeNames:       make(map[xml.Name]string),
+		elementNames:    make(map[xml.Name]string),
+		elementsByType:  make(map[xml.Name][]string),
+		goNames:         make(map[string]bool),
+		goTypes:         make(map[xml.Name]string),
+		elementGoTypes:  make(map[xml.Name]string),
+	}
+
+	for _, name := range reservedGoNames {
+		s.goNames[name] = true
+	}
+
+	for _, schema := range schemas {
+		for _, element := range schema.Elements {
+			if s.elements.add(xml.Name{Space: schema.TargetNamespace, Local: element.Name}, element) {
+				s.schemas[element] = schema
+			}
+		}
+
+		for _, complexType := range schema.ComplexTypes {
+			if s.complexTypes.add(xml.Name{Space: schema.TargetNamespace, Local: complexType.Name}, complexType) {
+				s.schemas[complexType] = schema
+			}
+		}
+
+		for _, simpleType := range schema.SimpleType {
+			if s.simpleTypes.add(xml.Name{Space: schema.TargetNamespace, Local: simpleType.Name}, simpleType) {
+				s.schemas[simpleType] = schema
+			}
+		}
+
+		for _, attribute := range schema.Attributes {
+			if s.attributes.add(xml.Name{Space: schema.TargetNamespace, Local: attribute.Name}, attribute) {
+				s.schemas[attribute] = schema
+			}
+		}
+
+		for _, group := range schema.Groups {
+			if s.groups.add(xml.Name{Space: schema.TargetNamespace, Local: group.Name}, group) {
+				s.schemas[group] = schema
+			}
+		}
+
+		for _, group := range schema.AttributeGroups {
+			if s.attributeGroups.add(xml.Name{Space: schema.TargetNamespace, Local: group.Name}, group) {
+				s.schemas[group] = schema
+			}
+		}
+	}
+
+	for _, schema := range schemas {
+		for _, element := range schema.Elements {
+			s.indexElementTypes(schema, []*xsd.Element{element})
+		}
+
+		for _, complexType := range schema.ComplexTypes {
+			s.indexComplexTypeElements(schema, complexType)
+		}
+
+		for _, group := range schema.Groups {
+			s.indexElementTypes(schema, group.Elements())
+		}
+	}
+
+	return s
+}
+
+// polymorphicNames and headNames are the names the generated code derives from the name of a polymorphic type and
+// from the name of the head of a substitution group.
+var (
+	polymorphicNames = []string{"%sInterface", "Any%s"}
+	headNames        = []string{"%sMember", "%sChoice"}
+)
+
+// derivedName returns the name derived from a Go name with a format, the name of the package of an imported type
+// being kept in front.
+func derivedName(format, name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i+1] + fmt.Sprintf(format, name[i+1:])
+	}
+
+	return fmt.Sprintf(format, name)
+}
+
+// name allots the Go names of the types and of the elements, along with the names derived from them. Types are
+// named first, so that they keep their name when an element of another type shares it, and the components are
+// named in the order the schemas declare them, which keeps the numbering of the names stable.
+func (s *symbols) name(schemas []*xsd.Schema, goName func(string) string, polymorphic, head func(xml.Name) bool) {
+	for _, schema := range schemas {
+		for _, simpleType := range schema.SimpleType {
+			s.nameType(schema, simpleType.Name, goName, nil)
+		}
+
+		for _, complexType := range schema.ComplexTypes {
+			var derived []string
+			if polymorphic(xml.Name{Space: schema.TargetNamespace, Local: complexType.Name}) {
+				derived = polymorphicNames
+			}
+
+			s.nameType(schema, complexType.Name, goName, derived)
+		}
+	}
+
+	for _, schema := range schemas {
+		for _, element := range schema.Elements {
+			var derived []string
+			if head(xml.Name{Space: schema.TargetNamespace, Local: element.Name}) {
+				derived = headNames
+			}
+
+			s.nameElement(schema, element, goName, derived)
+		}
+	}
+}
+
+// bind claims the names bindings give to types and elements, along with the names derived from them, before any
+// other name is allotted so that they are kept as given.
+func (s *symbols) bind(types, elements map[xml.Name]string, polymorphic, head func(xml.Name) bool) error {
+	claim := func(names map[xml.Name]string, component xml.Name, derived []string) error {
+		name := names[component]
+		if s.goNames[name] || !free(name, s.goNames, derived) {
+			return fmt.Errorf("%s is already taken, it cannot be given to {%s}%s", name, component.Space, component.Local)
+		}
+
+		unique(name, s.goNames, derived...)
+		return nil
+	}
+
+	for _, name := range sortedNames(types) {
+		var derived []string
+		if polymorphic(name) {
+			derived = polymorphicNames
+		}
+
+		if err := claim(types, name, derived); err != nil {
+			return err
+		}
+
+		s.typeNames[name] = types[name]
+	}
+
+	for _, name := range sortedNames(elements) {
+		var derived []string
+		if head(name) {
+			derived = headNames
+		}
+
+		if err := claim(elements, name, derived); err != nil {
+			return err
+		}
+
+		s.elementNames[name] = elements[name]
+	}
+
+	return nil
+}
+
+func (s *symbols) nameType(schema *xsd.Schema, name string, goName func(string) string, derived []string) {
+	qualifiedName := xml.Name{Space: schema.TargetNamespace, Local: name}
+	if _, ok := s.typeNames[qualifiedName]; !ok {
+		s.typeNames[qualifiedName] = s.claim(goName(name), qualifiedName, derived...)
+	}
+}
+
+// nameElement names the type generated for an element, which is its own type when it has the same name, unless
+// the names derived from the name of a head element are taken.
+func (s *symbols) nameElement(schema *xsd.Schema, element *xsd.Element, goName func(string) string, derived []string) {
+	qualifiedName := xml.Name{Space: schema.TargetNamespace, Local: element.Name}
+	if _, ok := s.elementNames[qualifiedName]; ok {
+		return
+	}
+
+	name := goName(element.Name)
+
+	if element.Type != "" {
+		if typeName, ok := s.typeNames[s.typeName(schemaScope(schema), element.Type)]; ok && typeName == name && free(name, s.goNames, derived) {
+			for _, format := range derived {
+				s.goNames[fmt.Sprintf(format, name)] = true
+			}
+
+			s.elementNames[qualifiedName] = name
+			return
+		}
+	}
+
+	s.elementNames[qualifiedName] = s.claim(name, qualifiedName, derived...)
+}
+
+// claim reserves the Go name of a component along with the names derived from it, numbering it when any of them is
+// already taken.
+func (s *symbols) claim(name string, qualifiedName xml.Name, derived ...string) string {
+	claimed := unique(name, s.goNames, derived...)
+	if claimed != name {
+		log.Printf("[WARN] %s is already taken, generating %s for {%s}%s", name, claimed, qualifiedName.Space, qualifiedName.Local)
+	}
+
+	return claimed
+}
+
+// reserve reserves a Go name, numbering it when it is already taken.
+func (s *symbols) reserve(name string) string {
+	return unique(name, s.goNames)
+}
+
+func (s *symbols) indexComplexTypeElements(schema *xsd.Schema, complexType *xsd.ComplexType) {
+	s.indexElementTypes(schema, complexType.Elements())
+	s.indexElementTypes(schema, complexType.ComplexContent.Extension.Elements())
+	s.indexElementTypes(schema, complexType.ComplexContent.Restriction.Elements())
+}
+
+func (s *symbols) indexElementTypes(schema *xsd.Schema, elements []*xsd.Element) {
+	for _, element := range elements {
+		if element.Type != "" {
+			typeName := s.typeName(schemaScope(schema), element.Type)
+			if !containsString(s.elementsByType[typeName], element.Name) {
+				s.elementsByType[typeName] = append(s.elementsByType[typeName], element.Name)
+			}
+		}
+
+		if element.ComplexType != nil {
+			s.indexComplexTypeElements(schema, element.ComplexType)
+		}
+	}
+}
+
+// typeName resolves a reference to a type, the type found in another namespace than the one of its prefix, such
+// as the types of chameleon schemas, being preferred over an unknown type.
+func (s *symbols) typeName(scope scope, name string) xml.Name {
+	qualifiedName := scope.resolve(name)
+	if qualifiedName.Space == soap.XmlNsSoapXsd {
+		return qualifiedName
+	}
+
+	if _, found, ok := s.complexTypes.get(qualifiedName); ok {
+		return found
+	}
+
+	if _, found, ok := s.simpleTypes.get(qualifiedName); ok {
+		return found
+	}
+
+	return qualifiedName
+}
+
+// elementName resolves a reference to an element.
+func (s *symbols) elementName(scope scope, name string) xml.Name {
+	qualifiedName := scope.resolve(name)
+
+	if _, found, ok := s.elements.get(qualifiedName); ok {
+		return found
+	}
+
+	return qualifiedName
+}
+
+// declaresType tells whether a simple or complex type has a name.
+func (s *symbols) declaresType(name xml.Name) bool {
+	_, complexType := s.complexTypes.components[name]
+	_, simpleType := s.simpleTypes.components[name]
+
+	return complexType || simpleType
+}
+
+// goType returns the Go type of a reference to a type: a built-in type for the XML Schema types, the type bindings
+// map it to, a pointer to the generated type otherwise.
+func (s *symbols) goType(scope scope, xsdType string, nillable bool) string {
+	qualifiedName := s.typeName(scope, xsdType)
+
+	if goType, ok := s.goTypes[qualifiedName]; ok {
+		if nillable {
+			return "*" + goType
+		}
+
+		return goType
+	}
+
+	if name, ok := s.typeNames[qualifiedName]; ok {
+		return "*" + name
+	}
+
+	return toGoType(qualifiedName.Local, nillable)
+}
+
+// goElementType returns the Go type of a reference to an element.
+func (s *symbols) goElementType(scope scope, ref string) string {
+	if goType, ok := s.elementGoTypes[s.elementName(scope, ref)]; ok {
+		return goType
+	}
+
+	if name, ok := s.elementNames[s.elementName(scope, ref)]; ok {
+		return "*" + name
+	}
+
+	return toGoType(ref, false)
+}
+
+// findNameByType returns the name of the element declared with a type, or the name of the type when no element
+// or several differently named ones are declared with it.
+func (s *symbols) findNameByType(typeName xml.Name) string {
+	if names := s.elementsByType[typeName]; len(names) == 1 {
+		return names[0]
+	}
+
+	return typeName.Local
+}
+
+// scope holds the namespaces declared where a prefixed name is used, either a schema or a WSDL document.
+type scope struct {
+	xmlns           map[string]string
+	targetNamespace string
+}
+
+func schemaScope(schema *xsd.Schema) scope {
+	return scope{xmlns: schema.Xmlns, targetNamespace: schema.TargetNamespace}
+}
+
+// resolve resolves the prefix of a name against the namespaces in scope. Unprefixed names are in the default
+// namespace, or in the target namespace when there is none, which is how most schemas omitting it are meant. The xml
+// prefix is bound to the XML namespace without being declared.
+func (s scope) resolve(name string) xml.Name {
+	prefix, local := "", name
+	if i := strings.Index(name, ":"); i >= 0 {
+		prefix, local = name[:i], name[i+1:]
+	}
+
+	namespace, ok := s.xmlns[prefix]
+	switch {
+	case !ok && prefix == "":
+		namespace = s.targetNamespace
+	case !ok && prefix == "xml":
+		namespace = soap.XmlNsXml
+	}
+
+	return xml.Name{Space: namespace, Local: local}
+}
+
+// sortedNames returns the names a map has, sorted by namespace then local name.
+func sortedNames(names map[xml.Name]string) []xml.Name {
+	sorted := make([]xml.Name, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Space != sorted[j].Space {
+			return sorted[i].Space < sorted[j].Space
+		}
+
+		return sorted[i].Local < sorted[j].Local
+	})
+
+	return sorted
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,298 @@
+package templates
+
+var Declarations = `
+{{define "Type"}}
+	{{$typeName := .Name}}
+	{{if .Doc}} {{.Doc | comment}} {{end}}
+	{{if .Union}}
+		{{template "Union" .}}
+	{{else if .IsStruct}}
+		type {{.Name}} struct {
+			{{template "Fields" .Fields}}
+		}
+	{{else}}
+		type {{.Name}} {{.Underlying}}
+	{{end}}
+	{{with .Constants}}
+	const (
+		{{range .}}
+			{{if .Doc}} {{.Doc | comment}} {{end}}
+			{{.Name}} {{.Type}} = "{{goString .Value}}" {{end}}
+	)
+	{{end}}
+	{{with .XSIType}}
+	func ({{$typeName}}) XSIType() xml.Name {
+		return xml.Name{Space: "{{.Space}}", Local: "{{.Local}}"}
+	}
+	{{end}}
+	{{if .Polymorphic}}
+		{{template "Polymorphic" .Name}}
+	{{end}}
+	{{if .SubstitutionGroupHead}}
+		{{template "SubstitutionGroup" .Name}}
+	{{end}}
+	{{if .ModelGroup}}
+		{{template "ModelGroup" .Name}}
+	{{end}}
+	{{if .GroupContent}}
+		{{template "GroupContent" .Name}}
+	{{end}}
+	{{if .ListItem}}
+		{{template "List" .}}
+	{{end}}
+	{{if .Mixed}}
+		{{template "Mixed" .}}
+	{{end}}
+	{{if .CanValidate}}
+		{{template "Validate" .}}
+	{{end}}
+	{{with .Presets}}
+	// {{$.Constructor}} creates a value of {{$typeName}} holding the default and fixed values of its schema.
+	func {{$.Constructor}}() *{{$typeName}} {
+		t := &{{$typeName}}{}
+		{{- range .}}
+			xsd.SetValue(&t.{{.Path}}, {{.Constant}})
+		{{- end}}
+		return t
+	}
+	{{end}}
+	{{with .FixedValue}}
+	func (a {{$typeName}}) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+		return xsd.MarshalFixedAttr(name, {{$.Underlying}}(a), {{.}})
+	}
+	{{end}}
+	{{range .Markers}}
+		func (*{{$typeName}}) {{.}}() {}
+	{{end}}
+	{{with .BodyElements}}
+	func (m *{{$typeName}}) BodyElements() []soap.BodyElement {
+		return []soap.BodyElement{
+			{{range .}}
+				{Name: xml.Name{Space: "{{.Name.Space}}", Local: "{{.Name.Local}}"}, Value: &m.{{.Field}}},
+			{{end}}
+		}
+	}
+	{{end}}
+{{end}}
+
+{{define "Fields"}}
+	{{range .}}
+		{{if .Doc}} {{.Doc | comment}} {{end}}
+		{{if .Embedded}}
+			{{.Type}}
+		{{else if not .Type}}
+			{{.Name}} {{if .Slice}}[]{{end}}struct {
+				{{template "Fields" .Struct}}
+			} ` + "`" + `{{.Tag}}` + "`" + `
+		{{else}}
+			{{.Name}} {{.Type}} ` + "`" + `{{.Tag}}` + "`" + `
+		{{end}}
+	{{end}}
+{{end}}
+
+{{define "Polymorphic"}}
+	// {{.}}Interface is implemented by {{.}} and by the types derived from it.
+	type {{.}}Interface interface {
+		is{{.}}()
+	}
+
+	// Any{{.}} holds a {{.}} or any type derived from it, told apart by their xsi:type.
+	type Any{{.}} struct {
+		Value {{.}}Interface
+	}
+
+	func (a Any{{.}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+		return XSITypes.Marshal(e, start, a.Value, (*{{.}})(nil))
+	}
+
+	func (a *Any{{.}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+		return XSITypes.Unmarshal(d, start, &a.Value, (*{{.}})(nil))
+	}
+
+	func (a Any{{.}}) Validate() error {
+		var v xsd.Validation
+		v.Value("", a.Value)
+		return v.Err()
+	}
+{{end}}
+
+{{define "SubstitutionGroup"}}
+	// {{.}}Member is implemented by the elements of the {{.}} substitution group.
+	type {{.}}Member interface {
+		is{{.}}Member()
+	}
+
+	// {{.}}Choice holds any element of the {{.}} substitution group, its XMLName telling which one.
+	type {{.}}Choice struct {
+		XMLName xml.Name
+		Value   {{.}}Member
+	}
+
+	func (c {{.}}Choice) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+		return SubstitutionGroups.Marshal(e, c.XMLName, c.Value)
+	}
+
+	func (c *{{.}}Choice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+		c.XMLName = start.Name
+		return SubstitutionGroups.Unmarshal(d, start, &c.Value)
+	}
+
+	func (c {{.}}Choice) Validate() error {
+		var v xsd.Validation
+		v.Value("", c.Value)
+		return v.Err()
+	}
+{{end}}
+
+{{define "Mixed"}}
+	{{$typeName := .Name}}
+	func (t *{{.Name}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+		return xsd.UnmarshalMixed(d, start, t
+		{{- range .MixedElements}},
+			xsd.MixedElement{Name: xml.Name{Space: "{{.Name.Space}}", Local: "{{.Name.Local}}"}, Value: new({{.Type}})}
+		{{- end}}
+		{{- if .MixedElements}},
+		{{end -}}
+		)
+	}
+	{{range .MixedElements}}
+		{{if .Accessor}}
+		// {{.Accessor}} returns the {{.Name.Local}} elements of the content of {{$typeName}}.
+		func (t {{$typeName}}) {{.Accessor}}() []{{.Type}} {
+			return xsd.MixedElements[{{.Type}}](t.Content, xml.Name{Space: "{{.Name.Space}}", Local: "{{.Name.Local}}"})
+		}
+		{{end}}
+	{{end}}
+{{end}}
+
+{{define "ModelGroup"}}
+	func (g {{.}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+		return soap.MarshalModelGroup(e, g)
+	}
+
+	func (g *{{.}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+		return soap.UnmarshalModelGroup(d, start, g)
+	}
+{{end}}
+
+{{define "GroupContent"}}
+	func (c {{.}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+		return soap.MarshalGroupContent(e, &c)
+	}
+
+	func (c *{{.}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+		return soap.UnmarshalGroupContent(d, start, c)
+	}
+{{end}}
+
+{{define "List"}}
+	func (l {{.Name}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+		return xsd.ListOf[{{.ListItem}}](l).MarshalXML(e, start)
+	}
+
+	func (l {{.Name}}) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+		return xsd.ListOf[{{.ListItem}}](l).MarshalXMLAttr(name)
+	}
+
+	func (l *{{.Name}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+		return (*xsd.ListOf[{{.ListItem}}])(l).UnmarshalXML(d, start)
+	}
+
+	func (l *{{.Name}}) UnmarshalXMLAttr(attr xml.Attr) error {
+		return (*xsd.ListOf[{{.ListItem}}])(l).UnmarshalXMLAttr(attr)
+	}
+{{end}}
+
+{{define "Union"}}
+	{{$typeName := .Name}}
+	type {{.Name}} struct {
+		member int
+		value  interface{}
+	}
+
+	{{range $i, $member := .Union}}
+		// {{.Constructor}} creates a {{$typeName}} holding a value of its {{.Name}} member type.
+		func {{.Constructor}}(v {{.Type}}) {{$typeName}} {
+			return {{$typeName}}{member: {{$i}}, value: v}
+		}
+
+		// {{.Name}} returns the value of the union when it belongs to its {{.Name}} member type.
+		func (u {{$typeName}}) {{.Name}}() ({{.Type}}, bool) {
+			v, ok := u.value.({{.Type}})
+			return v, ok && u.member == {{$i}}
+		}
+	{{end}}
+
+	func (u {{.Name}}) MarshalText() ([]byte, error) {
+		return xsd.FormatUnion(u.value)
+	}
+
+	func (u *{{.Name}}) UnmarshalText(text []byte) error {
+		member, value, err := xsd.ParseUnion(string(text),
+			{{- range .Union}}
+				xsd.UnionMember{Value: new({{.Type}}){{with .Enumeration}}, Enumeration: []string{ {{range .}}"{{goString .}}", {{end}} }{{end}} },
+			{{- end}}
+		)
+		if err != nil {
+			return err
+		}
+
+		u.member, u.value = member, value
+		return nil
+	}
+{{end}}
+
+{{define "Validate"}}
+	// Validate checks the value against the constraints of its schema, returning the ones it breaks as
+	// xsd.ValidationErrors.
+	func (t {{.Name}}) Validate() error {
+		var v xsd.Validation
+		{{- if .Union}}
+			v.Value("", t.value)
+		{{- else if .IsStruct}}
+			{{- range .Fields}}
+				{{- if ne .Name "XMLName"}}
+					{{- template "ValidateField" .}}
+				{{- end}}
+			{{- end}}
+		{{- else}}
+			{{- with .Facets}}
+				v.Facets("", t, {{template "Facets" .}})
+			{{- end}}
+			{{- if .ValidatesUnderlying}}
+				v.Value("", {{.Underlying}}(t))
+			{{- end}}
+		{{- end}}
+		return v.Err()
+	}
+{{end}}
+
+{{define "ValidateField"}}
+	{{- $name := .Name}}
+	{{- $selector := .Selector}}
+	{{- if .Required}}
+		v.Required("{{$name}}", t.{{$selector}})
+	{{- end}}
+	{{- with .Occurs}}
+		v.Occurs("{{$name}}", len(t.{{$selector}}), {{.Min}}, {{.Max}})
+	{{- end}}
+	{{- with .Facets}}
+		v.Facets("{{$name}}", t.{{$selector}}, {{template "Facets" .}})
+	{{- end}}
+	{{- if .HoldsValidated}}
+		v.Value("{{if not .Embedded}}{{$name}}{{end}}", t.{{$selector}})
+	{{- end}}
+{{- end}}
+
+{{define "Facets" -}}
+	xsd.Facets{
+		{{- with .Pattern}}Pattern: {{quote .}},{{end}}
+		{{- with .MinInclusive}}MinInclusive: {{quote .}},{{end}}
+		{{- with .MaxInclusive}}MaxInclusive: {{quote .}},{{end}}
+		{{- with .Length}}Length: {{quote .}},{{end}}
+		{{- with .MinLength}}MinLength: {{quote .}},{{end}}
+		{{- with .MaxLength}}MaxLength: {{quote .}},{{end}}
+		{{- with .Enumeration}}Enumeration: []string{ {{- range .}}{{quote .}}, {{end -}} },{{end -}}
+	}
+{{- end}}
+`
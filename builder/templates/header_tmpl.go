@@ -3,20 +3,23 @@ package templates
 var Header = `
 // Code generated by gowsdlsoap DO NOT EDIT.
 
-package {{.}}
+package {{.Name}}
 
 import (
 	"encoding/xml"
 	"time"
 
-	{{/*range .Imports*/}}
-		{{/*.*/}}
-	{{/*end*/}}
+	{{range .Imports}}
+		{{if not (eq .Alias "xml" "time")}}{{.Alias}} "{{.Path}}"{{end}}
+	{{end}}
 )
 
 // against "unused imports"
 var _ time.Time
 var _ xml.Name
+{{range .Imports}}
+	var _ {{.Type}}
+{{end}}
 
 type AnyType struct {
 	InnerXML string ` + "`" + `xml:",innerxml"` + "`" + `
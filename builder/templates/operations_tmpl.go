@@ -3,61 +3,89 @@ package templates
 var Operations = `
 // Code generated by gowsdlsoap DO NOT EDIT.
 
-package {{packageName}}
+package {{.Name}}
 
 import (
 	"context"
+	"encoding/xml"
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
+	"github.com/go-aegian/gowsdlsoap/builder/xsd"
 	"github.com/go-aegian/gowsdlsoap/proxy"
+	{{range .Imports}}
+		{{if not (eq .Alias "context" "xml" "soap" "xsd" "proxy")}}{{.Alias}} "{{.Path}}"{{end}}
+	{{end}}
 )
 
-{{range .}}
-	{{$privateType := .Name | makePrivate}}
-	{{$exportType := .Name | makePublic}}
+// against "unused imports"
+var _ xml.Name
+var _ soap.BodyElements
+var _ xsd.DateTime
+{{range .Imports}}
+	var _ {{.Type}}
+{{end}}
+
+{{range .Services}}
+	{{$implementation := .Implementation}}
 
-	type {{$exportType}} interface {
+	type {{.Name}} interface {
 		{{range .Operations}}
-			{{$faults := len .Faults}}
-			{{$soapAction := findSOAPAction .Name $privateType}}
-			{{$requestType := findMessageType .Input.Message | replaceReservedWords | makePublic}}
-			{{$responseType := findMessageType .Output.Message | replaceReservedWords | makePublic}}
-
-			{{/*if ne $soapAction ""*/}}
-			{{if gt $faults 0}}
+			{{if .Faults}}
 			// Error can be either of the following types:
 			// {{range .Faults}}
 			//   - {{.Name}} {{.Doc}}{{end}}{{end}}
 			{{if ne .Doc ""}}/* {{.Doc}} */{{end}}
-			{{makePublic .Name | replaceReservedWords}} ({{if ne $requestType ""}}request *{{$requestType}}{{end}}) ({{if ne $responseType ""}}*{{$responseType}}, {{end}}error)
-			{{/*end*/}}
-			{{makePublic .Name | replaceReservedWords}}Context (ctx context.Context, {{if ne $requestType ""}}request *{{$requestType}}{{end}}) ({{if ne $responseType ""}}*{{$responseType}}, {{end}}error)
-			{{/*end*/}}
+			{{.Name}} ({{if .Request}}request *{{.Request}}{{end}}) ({{if .Response}}*{{.Response}}, {{end}}error)
+
+			{{.Name}}Context (ctx context.Context, {{if .Request}}request *{{.Request}}{{end}}) ({{if .Response}}*{{.Response}}, {{end}}error)
 		{{end}}
 	}
 
-	type {{$privateType}} struct {
+	type {{$implementation}} struct {
 		client *proxy.Client
 	}
 
-	func New{{$exportType}}(client *proxy.Client) {{$exportType}} {
-		return &{{$privateType}}{client: client}
+	{{if .ClientOptions}}
+		// New{{.Name}} returns the {{.Name}} service.
+		// Its operations are called through a copy of client set up for {{.ClientSetup}},
+		// the client itself being left as it is.
+	{{end -}}
+	func New{{.Name}}(client *proxy.Client) {{.Name}} {
+		{{if .ClientOptions}}
+			client = client.Clone({{range $i, $option := .ClientOptions}}{{if $i}}, {{end}}{{$option}}{{end}})
+		{{end}}
+		return &{{$implementation}}{client: client}
 	}
 
 	{{range .Operations}}
-		{{$requestType := findMessageType .Input.Message | replaceReservedWords | makePublic}}
-		{{$soapAction := findSOAPAction .Name $privateType}}
-		{{$responseType := findMessageType .Output.Message | replaceReservedWords | makePublic}}
-		func (service *{{$privateType}}) {{makePublic .Name | replaceReservedWords}}Context (ctx context.Context, {{if ne $requestType ""}}request *{{$requestType}}{{end}}) ({{if ne $responseType ""}}*{{$responseType}}, {{end}}error) {
-			{{if ne $responseType ""}}response := new({{$responseType}}){{end}}
-			err := service.client.CallContext(ctx, "{{if ne $soapAction ""}}{{$soapAction}}{{else}}''{{end}}", {{if ne $requestType ""}}request{{else}}nil{{end}}, {{if ne $responseType ""}}response{{else}}struct{}{}{{end}})
+		{{range .Types}}
+			{{template "Type" .}}
+		{{end}}
+		{{if .OptionalResponse}}
+			// {{.Name}}Context calls an in-optional-out operation, the response being nil when the service replies
+			// with nothing.
+		{{else if and (not .Response) .Faults}}
+			// {{.Name}}Context calls a robust-in-only operation: the service replies with nothing, or with one of the
+			// faults of the operation returned as the error.
+		{{else if not .Response}}
+			// {{.Name}}Context calls an in-only operation: the service replies with nothing, a fault it may still
+			// reply with being returned as the error.
+		{{end -}}
+		func (service *{{$implementation}}) {{.Name}}Context (ctx context.Context, {{if .Request}}request *{{.Request}}{{end}}) ({{if .Response}}*{{.Response}}, {{end}}error) {
+			{{if .Response}}response := new({{.Response}}){{end}}
+			{{if .OptionalResponse}}replied, err := service.client.CallOptionalContext(ctx, "{{.Action}}", {{if .Request}}request{{else}}nil{{end}}, response){{else if .Response}}err := service.client.CallContext(ctx, "{{.Action}}", {{if .Request}}request{{else}}nil{{end}}, response){{else}}err := service.client.CallOneWayContext(ctx, "{{.Action}}", {{if .Request}}request{{else}}nil{{end}}){{end}}
 			if err != nil {
-				return {{if ne $responseType ""}}nil, {{end}}err
+				return {{if .Response}}nil, {{end}}err
 			}
-
-			return {{if ne $responseType ""}}response, {{end}}nil
+			{{if .OptionalResponse}}
+				if !replied {
+					return nil, nil
+				}
+			{{end}}
+			return {{if .Response}}response, {{end}}nil
 		}
 
-		func (service *{{$privateType}}) {{makePublic .Name | replaceReservedWords}} ({{if ne $requestType ""}}request *{{$requestType}}{{end}}) ({{if ne $responseType ""}}*{{$responseType}}, {{end}}error) {
-			return service.{{makePublic .Name | replaceReservedWords}}Context(context.Background(), {{if ne $requestType ""}}request{{end}})
+		func (service *{{$implementation}}) {{.Name}} ({{if .Request}}request *{{.Request}}{{end}}) ({{if .Response}}*{{.Response}}, {{end}}error) {
+			return service.{{.Name}}Context(context.Background(), {{if .Request}}request{{end}})
 		}
 	{{end}}
 {{end}}
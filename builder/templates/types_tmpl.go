@@ -3,241 +3,60 @@ package templates
 var Types = `
 // Code generated by gowsdlsoap DO NOT EDIT.
 
-package {{packageName}}
+package {{.Name}}
 
 import (
 	"encoding/xml"
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
 	"github.com/go-aegian/gowsdlsoap/builder/xsd"
-)
-
-{{define "SimpleType"}}
-	{{$typeName := replaceReservedWords .Name | makePublic}}
-	{{if .Doc}} {{.Doc | comment}} {{end}}
-	{{if ne .List.ItemType ""}}
-		type {{$typeName}} []{{toGoType .List.ItemType false | stripPointerFromType}}
-	{{else if ne .Union.MemberTypes ""}}
-		type {{$typeName}} string
-	{{else if .Union.SimpleType}}
-		type {{$typeName}} string
-	{{else if .Restriction.Base}}
-		type {{$typeName}} {{toGoType .Restriction.Base false | stripPointerFromType}}
-    {{else}}
-		type {{$typeName}} interface{}
-	{{end}}
-	{{if .Restriction.Enumeration}}
-	const (
-		{{with .Restriction}}
-			{{range .Enumeration}}
-				{{if .Doc}} {{.Doc | comment}} {{end}}
-				{{$typeName}}{{$value := replaceReservedWords .Value}}{{$value | makePublic}} {{$typeName}} = "{{goString .Value}}" {{end}}
-		{{end}}
-	)
+	{{range .Imports}}
+		{{if not (eq .Alias "xml" "soap" "xsd")}}{{.Alias}} "{{.Path}}"{{end}}
 	{{end}}
-{{end}}
+)
 
-{{define "ComplexContent"}}
-	{{$baseType := toGoType .Extension.Base false}}
-	{{ if $baseType }}
-		{{$baseType}}
-	{{end}}
-	{{template "Elements" .Extension.Sequence}}
-	{{template "Elements" .Extension.Choice}}
-	{{template "Elements" .Extension.SequenceChoice}}
-	{{template "Attributes" .Extension.Attributes}}
+// against "unused imports"
+var _ xml.Name
+var _ xsd.DateTime
+var _ soap.XSITyper
+{{range .Imports}}
+	var _ {{.Type}}
 {{end}}
 
-{{define "Attributes"}}
-	{{range .}}
-		{{if .Doc}} {{.Doc | comment}} {{end}}
-		{{ if ne .Type "" }}
-			{{$type := findNameByType .Name}}
-			{{$namespace := getNSFromType $type }}
-			{{if ne $namespace ""}}
-				{{$namespace = printf "%s " $namespace}}
-			{{end}}
-			{{ normalize .Name | makeFieldPublic}} {{toGoType .Type false}} ` + "`" + `xml:"{{.Name}},attr,omitempty" json:"{{$namespace}}{{.Name}},omitempty"` + "`" + `
-		{{ else }}
-			{{ normalize .Name | makeFieldPublic}} string ` + "`" + `xml:"{{.Name}},attr,omitempty" json:"{{.Name}},omitempty"` + "`" + `
-		{{ end }}
-	{{end}}
+{{range .Types}}
+	{{template "Type" .}}
 {{end}}
 
-{{define "SimpleContent"}}
-	Value {{toGoType .Extension.Base false}} ` + "`xml:\",chardata\" json:\"-,\"`" + `
-	{{template "Attributes" .Extension.Attributes}}
-{{end}}
+{{with .SubstitutionGroupMembers}}
+	// SubstitutionGroups maps the names of the elements of substitution groups to their Go types.
+	var SubstitutionGroups = soap.NewElementRegistry()
 
-{{define "ComplexTypeInline"}}
-	{{replaceReservedWords .Name | makePublic}} {{if eq .MaxOccurs "unbounded"}}[]{{end}}struct {
-	{{with .ComplexType}}
-		{{if ne .ComplexContent.Extension.Base ""}}
-			{{template "ComplexContent" .ComplexContent}}
-		{{else if ne .SimpleContent.Extension.Base ""}}
-			{{template "SimpleContent" .SimpleContent}}
-		{{else}}
-			{{template "Elements" .Sequence}}
-			{{template "Elements" .Choice}}
-			{{template "Elements" .SequenceChoice}}
-			{{template "Elements" .All}}
-			{{template "Attributes" .Attributes}}
+	func init() {
+		{{range .}}
+			SubstitutionGroups.Register(xml.Name{Space: "{{.Name.Space}}", Local: "{{.Name.Local}}"}, (*{{.GoType}})(nil))
 		{{end}}
-	{{end}}
-	} ` + "`" + `{{.Name}},omitempty" json:"{{.Name}},omitempty"` + "`" + `
+	}
 {{end}}
 
-{{define "Elements"}}
-	{{ $targetNamespace := getNamespace }}
-	{{range .}}
-		{{if ne .Ref ""}}
-			{{stripAliasNSFromType .Ref | replaceReservedWords  | makePublic}} {{if eq .MaxOccurs "unbounded"}}[]{{end}}{{toGoType .Ref .Nillable }} ` + "`" + `xml:"{{.Ref | stripAliasNSFromType}},omitempty" json:"{{.Ref | stripAliasNSFromType}},omitempty"` + "`" + `
-		{{else}}
-			{{if not .Type}}
-				{{if .SimpleType}}
-					{{if .Doc}} {{.Doc | comment}} {{end}}
-					{{if ne .SimpleType.List.ItemType ""}}
-						{{ normalize .Name | makeFieldPublic}} []{{toGoType .SimpleType.List.ItemType false}} ` + "`" + `xml:"{{.Name}},omitempty" json:"{{.Name}},omitempty"` + "`" + `
-					{{else}}
-						{{ normalize .Name | makeFieldPublic}} {{toGoType .SimpleType.Restriction.Base false}} ` + "`" + `xml:"{{.Name}},omitempty" json:"{{.Name}},omitempty"` + "`" + `
-					{{end}}
-				{{else}}
-					{{template "ComplexTypeInline" .}}
-				{{end}}
-			{{else}}
-				{{if .Doc}} {{.Doc | comment}} {{end}}
-				{{$namespace:=printf "%s " getNamespace}}
-				{{replaceAttrReservedWords .Name | makeFieldPublic}} {{if eq .MaxOccurs "unbounded"}}[]{{end}}{{toGoType .Type .Nillable }} ` + "`" + `xml:"{{$namespace}}{{.Name}},omitempty" json:"{{.Name}},omitempty"` + "`" + `
-			{{end}}
-		{{end}}
-	{{end}}
-{{end}}
+{{with .XSITypes}}
+	// XSITypes maps the xsi:type names of the complex types taking part in a type hierarchy to their Go types.
+	var XSITypes = soap.NewTypeRegistry()
 
-{{define "Any"}}
-	{{range .}}
-		Items     []string ` + "`" + `xml:",any" json:"items,omitempty"` + "`" + `
-	{{end}}
-{{end}}
-
-{{range .Schemas}}
-	{{ $targetNamespace := setNamespace .TargetNamespace }}
-	{{range .SimpleType}}
-		{{template "SimpleType" .}}
-	{{end}}
-	{{range .Elements}}
-		{{$name := .Name}}
-		{{$typeName := replaceReservedWords $name | makePublic}}
-		{{if not .Type}}
-			{{/* ComplexTypeLocal */}}
-			{{with .ComplexType}}
-				type {{$typeName}} struct {
-					{{$type := findNameByType .Name}}
-					{{$namespace := printf "%s " $targetNamespace }}
-					XMLName xml.Name ` + "`xml:\"{{$namespace}}{{$name}}\"`" + `
-					{{if ne .ComplexContent.Extension.Base ""}}
-						{{template "ComplexContent" .ComplexContent}}
-					{{else if ne .SimpleContent.Extension.Base ""}}
-						{{template "SimpleContent" .SimpleContent}}
-					{{else}}
-						{{template "Elements" .Sequence}}
-						{{template "Any" .Any}}
-						{{template "Elements" .Choice}}
-						{{template "Elements" .SequenceChoice}}
-						{{template "Elements" .All}}
-						{{template "Attributes" .Attributes}}
-					{{end}}
-				}
-			{{end}}
-			{{/* SimpleTypeLocal */}}
-			{{with .SimpleType}}
-				{{if .Doc}} {{.Doc | comment}} {{end}}
-				{{if ne .List.ItemType ""}}
-					type {{$typeName}} []{{toGoType .List.ItemType false | stripPointerFromType}}
-				{{else if ne .Union.MemberTypes ""}}
-					type {{$typeName}} string
-				{{else if .Union.SimpleType}}
-					type {{$typeName}} string
-				{{else if .Restriction.Base}}
-					type {{$typeName}} {{toGoType .Restriction.Base false | stripPointerFromType}}
-				{{else}}
-					type {{$typeName}} interface{}
-				{{end}}
-				{{if .Restriction.Enumeration}}
-				const (
-					{{with .Restriction}}
-						{{range .Enumeration}}
-							{{if .Doc}} {{.Doc | comment}} {{end}}
-							{{$typeName}}{{$value := replaceReservedWords .Value}}{{$value | makePublic}} {{$typeName}} = "{{goString .Value}}" {{end}}
-					{{end}}
-				)
-				{{end}}
-			{{end}}
-		{{else}}
-			{{$type := toGoType .Type .Nillable | stripPointerFromType}}
-			{{if ne ($typeName) ($type)}}
-				type {{$typeName}} {{$type}}
-				{{if eq ($type) ("soap.XSDDateTime")}}
-					func (xdt {{$typeName}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-						return soap.XSDDateTime(xdt).MarshalXML(e, start)
-					}
-
-					func (xdt *{{$typeName}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-						return (*soap.XSDDateTime)(xdt).UnmarshalXML(d, start)
-					}
-				{{else if eq ($type) ("soap.XSDDate")}}
-					func (xd {{$typeName}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-						return soap.XSDDate(xd).MarshalXML(e, start)
-					}
-
-					func (xd *{{$typeName}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-						return (*soap.XSDDate)(xd).UnmarshalXML(d, start)
-					}
-				{{else if eq ($type) ("soap.XSDTime")}}
-					func (xt {{$typeName}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-						return soap.XSDTime(xt).MarshalXML(e, start)
-					}
-
-					func (xt *{{$typeName}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-						return (*soap.XSDTime)(xt).UnmarshalXML(d, start)
-					}
-				{{end}}
-			{{end}}
+	func init() {
+		{{range .}}
+			XSITypes.Register(xml.Name{Space: "{{.Name.Space}}", Local: "{{.Name.Local}}"}, (*{{.GoType}})(nil))
 		{{end}}
-	{{end}}
-	{{range .ComplexTypes}}
-		{{/* ComplexTypeGlobal */}}
-		{{$typeName := replaceReservedWords .Name | makePublic}}
-		{{if and (eq (len .SimpleContent.Extension.Attributes) 0) (eq (toGoType .SimpleContent.Extension.Base false) "string") }}
-			type {{$typeName}} string
-		{{else}}
-			type {{$typeName}} struct {
-
-				{{$type := findNameByType .Name}}
-				{{$type = stripAliasNSFromType $type}}
-
-				{{$ns := printf "%s " $targetNamespace}}
-
-				{{$isAbstract := isAbstract $typeName false}}
-
-				{{$fullType := printf "%sType" $type}}
+	}
+{{end}}
 
-				{{$hasXMLName := and (eq .Name $fullType) (eq $isAbstract false)}}
+{{with .GlobalElements}}
+	// GlobalElements maps the names of the global elements to their Go types, so that the content of wildcards can
+	// be decoded with GlobalElements.Decode.
+	var GlobalElements = soap.NewElementRegistry()
 
-				{{if $hasXMLName}}
-					XMLName xml.Name ` + "`xml:\"{{$ns}}{{$type}}\"`" + `
-				{{end}}
-				{{if ne .ComplexContent.Extension.Base ""}}
-					{{template "ComplexContent" .ComplexContent}}
-				{{else if ne .SimpleContent.Extension.Base ""}}
-					{{template "SimpleContent" .SimpleContent}}
-				{{else}}
-					{{template "Elements" .Sequence}}
-					{{template "Any" .Any}}
-					{{template "Elements" .Choice}}
-					{{template "Elements" .SequenceChoice}}
-					{{template "Elements" .All}}
-					{{template "Attributes" .Attributes}}
-				{{end}}
-			}
+	func init() {
+		{{range .}}
+			GlobalElements.Register(xml.Name{Space: "{{.Name.Space}}", Local: "{{.Name.Local}}"}, (*{{.GoType}})(nil))
 		{{end}}
-	{{end}}
+	}
 {{end}}
 `
@@ -0,0 +1,1145 @@
+package builder
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-aegian/gowsdlsoap/builder/model"
+	"github.com/go-aegian/gowsdlsoap/builder/xsd"
+)
+
+// typesModeler lowers the global components of a schema into the Go types of the model, references being resolved
+// in the scope of the schema.
+type typesModeler struct {
+	b      *Builder
+	schema *xsd.Schema
+	scope  scope
+
+	// detached holds what the elements sharing their Go type with a complex type add to that type
+	detached []*model.Type
+
+	// repeated holds the repeated model groups met among the fields of the struct being modeled, by the field
+	// standing for them, and localTypes the types generated for the fields of that struct, such as the group
+	// structs holding repeated groups
+	repeated   map[*model.Field]*repeatedGroup
+	localTypes []*model.Type
+}
+
+// repeatedGroup is a model group occurring several times along with the fields of one of its occurrences.
+type repeatedGroup struct {
+	kind   string
+	fields []*model.Field
+}
+
+func newTypesModeler(b *Builder, schema *xsd.Schema) *typesModeler {
+	return &typesModeler{b: b, schema: schema, scope: schemaScope(schema), repeated: make(map[*model.Field]*repeatedGroup)}
+}
+
+func (m *typesModeler) types() []*model.Type {
+	var types []*model.Type
+
+	for _, simpleType := range m.schema.SimpleType {
+		if !m.b.generates(m.qualify(simpleType.Name), false) {
+			continue
+		}
+
+		t := m.simpleType(m.b.goTypeName(m.qualify(simpleType.Name)), simpleType)
+		if m.b.usesEncoding() {
+			t.XSIType = &xml.Name{Space: m.schema.TargetNamespace, Local: simpleType.Name}
+		}
+
+		types = append(types, t)
+	}
+
+	for _, element := range m.schema.Elements {
+		if !m.b.generates(m.qualify(element.Name), true) {
+			continue
+		}
+
+		if t := m.element(element); t != nil {
+			types = append(types, t)
+		}
+
+		types = append(types, m.takeLocalTypes()...)
+	}
+
+	for _, complexType := range m.schema.ComplexTypes {
+		if !m.b.generates(m.qualify(complexType.Name), false) {
+			continue
+		}
+
+		types = append(types, m.complexType(complexType))
+		types = append(types, m.takeLocalTypes()...)
+	}
+
+	return types
+}
+
+// qualify returns the qualified name of a component declared by the schema.
+func (m *typesModeler) qualify(name string) xml.Name {
+	return xml.Name{Space: m.schema.TargetNamespace, Local: name}
+}
+
+func (m *typesModeler) toGoType(xsdType string, nillable bool) string {
+	return m.b.symbols.goType(m.scope, xsdType, nillable)
+}
+
+// toGoElementType returns the Go type of an element, an element of a polymorphic type being held by the
+// wrapper generated to marshal its xsi:type.
+func (m *typesModeler) toGoElementType(xsdType string, nillable bool) string {
+	if name := m.b.symbols.typeName(m.scope, xsdType); m.b.isPolymorphic(name) && !m.b.binder.omits(name, false) {
+		return "*" + derivedName("Any%s", m.b.goTypeName(name))
+	}
+
+	return m.toGoType(xsdType, nillable)
+}
+
+func (m *typesModeler) simpleType(name string, simpleType *xsd.SimpleType) *model.Type {
+	t := &model.Type{Name: name, Doc: simpleType.Doc}
+
+	switch {
+	case simpleType.List.ItemType != "" || simpleType.List.SimpleType != nil:
+		t.ListItem = m.listItem(simpleType.List)
+		t.Underlying = "[]" + t.ListItem
+	case simpleType.Union.MemberTypes != "" || simpleType.Union.SimpleType != nil:
+		t.Union = m.unionMembers(simpleType.Union)
+	case simpleType.Restriction.Base != "":
+		if t.Union = m.unionOf(simpleType.Restriction.Base); t.Union == nil {
+			t.Underlying = stripPointerFromType(m.toGoType(simpleType.Restriction.Base, false))
+			t.ListItem = m.listItemOf(simpleType.Restriction.Base)
+		}
+	default:
+		t.Underlying = "interface{}"
+	}
+
+	if t.Union == nil {
+		t.Constants = m.enumeration(name, name, simpleType.Restriction.Enumeration)
+		t.Facets = facets(name, simpleType.Restriction)
+	}
+
+	return t
+}
+
+// listItem returns the Go type of the items of a list.
+func (m *typesModeler) listItem(list xsd.List) string {
+	switch {
+	case list.ItemType != "":
+		return stripPointerFromType(m.toGoType(list.ItemType, false))
+	case list.SimpleType != nil && list.SimpleType.Restriction.Base != "":
+		return stripPointerFromType(m.toGoType(list.SimpleType.Restriction.Base, false))
+	}
+
+	return "string"
+}
+
+// listItemOf returns the Go type of the items of a list type, or an empty string when the type is no list. Types
+// declared from a list type are lists too, which need the methods writing their items.
+func (m *typesModeler) listItemOf(xsdType string) string {
+	simpleType, declaring, ok := m.declaredSimpleType(xsdType)
+	if !ok || simpleType.List.ItemType == "" && simpleType.List.SimpleType == nil {
+		return ""
+	}
+
+	return declaring.listItem(simpleType.List)
+}
+
+// unionMembers returns the member types of a union, in the order they are declared.
+func (m *typesModeler) unionMembers(union xsd.Union) []*model.UnionMember {
+	var members []*model.UnionMember
+
+	add := func(name, goType string, enumeration []xsd.RestrictionValue) {
+		if name != "" {
+			name = m.b.fieldName(name)
+		}
+
+		if name == "" || containsUnionMember(members, name) {
+			name = fmt.Sprintf("Member%d", len(members)+1)
+		}
+
+		member := &model.UnionMember{Name: name, Type: goType}
+		for _, value := range enumeration {
+			member.Enumeration = append(member.Enumeration, value.Value)
+		}
+
+		members = append(members, member)
+	}
+
+	for _, memberType := range strings.Fields(union.MemberTypes) {
+		var enumeration []xsd.RestrictionValue
+		if simpleType, _, ok := m.declaredSimpleType(memberType); ok {
+			enumeration = simpleType.Restriction.Enumeration
+		}
+
+		add(stripAliasNSFromType(memberType), stripPointerFromType(m.toGoType(memberType, false)), enumeration)
+	}
+
+	for _, simpleType := range union.SimpleType {
+		goType := "string"
+		switch {
+		case simpleType.List.ItemType != "" || simpleType.List.SimpleType != nil:
+			goType = "xsd.ListOf[" + m.listItem(simpleType.List) + "]"
+		case simpleType.Restriction.Base != "":
+			goType = stripPointerFromType(m.toGoType(simpleType.Restriction.Base, false))
+		}
+
+		add("", goType, simpleType.Restriction.Enumeration)
+	}
+
+	return members
+}
+
+// unionOf returns the member types of a union type, or nil when the type is no union. Types declared from a union
+// type are unions too, holding values of the same member types.
+func (m *typesModeler) unionOf(xsdType string) []*model.UnionMember {
+	simpleType, declaring, ok := m.declaredSimpleType(xsdType)
+	switch {
+	case !ok:
+		return nil
+	case simpleType.Union.MemberTypes != "" || simpleType.Union.SimpleType != nil:
+		return declaring.unionMembers(simpleType.Union)
+	case simpleType.Restriction.Base != "":
+		return declaring.unionOf(simpleType.Restriction.Base)
+	}
+
+	return nil
+}
+
+// declaredSimpleType returns a global simple type along with the modeler of the schema declaring it, which
+// resolves the names it uses.
+func (m *typesModeler) declaredSimpleType(xsdType string) (*xsd.SimpleType, *typesModeler, bool) {
+	simpleType, _, ok := m.b.symbols.simpleTypes.get(m.b.symbols.typeName(m.scope, xsdType))
+	if !ok {
+		return nil, nil, false
+	}
+
+	return simpleType, newTypesModeler(m.b, m.b.symbols.schemas[simpleType]), true
+}
+
+// facets returns the facets of a restriction Validate checks, or nil when it has none.
+// facets returns the facets of a restriction constraining the values of the type or field name, warning about the
+// patterns the values can't be checked against.
+func facets(name string, restriction xsd.Restriction) *model.Facets {
+	f := &model.Facets{
+		Pattern:      restriction.Pattern.Value,
+		MinInclusive: restriction.MinInclusive.Value,
+		MaxInclusive: restriction.MaxInclusive.Value,
+		Length:       restriction.Length.Value,
+		MinLength:    restriction.MinLength.Value,
+		MaxLength:    restriction.MaxLength.Value,
+	}
+
+	for _, value := range restriction.Enumeration {
+		f.Enumeration = append(f.Enumeration, value.Value)
+	}
+
+	if f.Pattern != "" {
+		if err := xsd.CheckPattern(f.Pattern); err != nil {
+			log.Printf("[WARN] Pattern %q of %s can't be compiled, its values won't be checked against it: %s", f.Pattern, name, err)
+		}
+	}
+
+	if f.Pattern+f.MinInclusive+f.MaxInclusive+f.Length+f.MinLength+f.MaxLength == "" && len(f.Enumeration) == 0 {
+		return nil
+	}
+
+	return f
+}
+
+func (m *typesModeler) enumeration(typeName, valueType string, values []xsd.RestrictionValue) []*model.Constant {
+	var constants []*model.Constant
+
+	for _, value := range values {
+		constants = append(constants, &model.Constant{
+			Name:  m.b.symbols.reserve(typeName + m.b.goName(value.Value)),
+			Type:  valueType,
+			Value: value.Value,
+			Doc:   value.Doc,
+		})
+	}
+
+	return constants
+}
+
+// element returns the type generated for a global element, or nil when it has none of its own.
+func (m *typesModeler) element(element *xsd.Element) *model.Type {
+	name := m.qualify(element.Name)
+	typeName := m.b.goElementName(name)
+
+	var t *model.Type
+
+	switch {
+	case element.Type != "":
+		if underlying := stripPointerFromType(m.toGoType(element.Type, element.Nillable)); underlying != typeName {
+			t = &model.Type{Name: typeName, Underlying: underlying, ListItem: m.listItemOf(element.Type)}
+			if t.Union = m.unionOf(element.Type); t.Union != nil {
+				t.Underlying = ""
+			}
+		}
+
+	case element.ComplexType != nil:
+		t = &model.Type{Name: typeName}
+		t.Fields = append(t.Fields, xmlNameField(fmt.Sprintf("%s %s", m.schema.TargetNamespace, element.Name)))
+		if isMixed(element.ComplexType) {
+			t.Fields = append(t.Fields, m.mixedContent(t, element.ComplexType)...)
+			break
+		}
+
+		t.Fields = append(t.Fields, m.content(typeName, element.ComplexType, true)...)
+		t.Constants = m.simpleContentEnumeration(typeName, element.ComplexType)
+
+	case element.SimpleType != nil:
+		t = m.simpleType(typeName, element.SimpleType)
+	}
+
+	extended := t
+	if extended == nil {
+		extended = &model.Type{Name: typeName}
+	}
+
+	extended.SubstitutionGroupHead = m.b.isSubstitutionGroupHead(name)
+	for _, head := range m.b.substitutionGroupHeads(name) {
+		extended.Markers = append(extended.Markers, "is"+head+"Member")
+	}
+
+	if t == nil && (extended.SubstitutionGroupHead || len(extended.Markers) > 0) {
+		m.detached = append(m.detached, extended)
+	}
+
+	return t
+}
+
+func (m *typesModeler) complexType(complexType *xsd.ComplexType) *model.Type {
+	name := m.qualify(complexType.Name)
+	t := &model.Type{Name: m.b.goTypeName(name)}
+
+	arrayItemType := m.b.arrayItemType(m.scope, complexType)
+
+	switch {
+	case arrayItemType != "":
+		t.Underlying = "[]" + arrayItemType
+
+	case len(complexType.SimpleContent.Extension.Attributes) == 0 && m.toGoType(complexType.SimpleContent.Extension.Base, false) == "string":
+		t.Underlying = "string"
+
+	default:
+		// a type only used by elements named after it is bound to that name
+		elementName := m.b.findNameByType(name)
+		if complexType.Name == elementName+"Type" && !m.b.isAbstract(name, false) {
+			t.Fields = append(t.Fields, xmlNameField(fmt.Sprintf("%s %s", m.schema.TargetNamespace, elementName)))
+		}
+
+		if isMixed(complexType) {
+			t.Fields = append(t.Fields, m.mixedContent(t, complexType)...)
+			break
+		}
+
+		t.Fields = append(t.Fields, m.content(t.Name, complexType, true)...)
+		t.Constants = m.simpleContentEnumeration(t.Name, complexType)
+	}
+
+	if m.b.usesEncoding() && arrayItemType == "" {
+		t.XSIType = &xml.Name{Space: m.schema.TargetNamespace, Local: complexType.Name}
+	}
+
+	t.Polymorphic = m.b.isPolymorphic(name)
+	for _, base := range m.b.xsiTypeBases(name) {
+		t.Markers = append(t.Markers, "is"+base)
+	}
+
+	return t
+}
+
+func (m *typesModeler) simpleContentEnumeration(typeName string, complexType *xsd.ComplexType) []*model.Constant {
+	restriction := complexType.SimpleContent.Restriction
+	if len(restriction.Enumeration) == 0 {
+		return nil
+	}
+
+	return m.enumeration(typeName, stripPointerFromType(m.toGoType(restriction.ValueType, false)), restriction.Enumeration)
+}
+
+// content returns the fields of a complex type, owner being the struct holding them. Wildcards are only kept for
+// named types and global elements.
+func (m *typesModeler) content(owner string, complexType *xsd.ComplexType, withAny bool) []*model.Field {
+	var fields []*model.Field
+
+	switch {
+	case complexType.ComplexContent.Extension.Base != "":
+		extension := complexType.ComplexContent.Extension
+		fields = append(fields, &model.Field{Type: m.toGoType(extension.Base, false), Embedded: true})
+		fields = append(fields, m.particles(owner, &extension.ContentModel, withAny)...)
+		fields = append(fields, m.attributes(owner, extension.Attributes)...)
+		fields = append(fields, m.attributeWildcard(extension.AnyAttribute, extension.Base)...)
+
+	case complexType.SimpleContent.Extension.Base != "":
+		extension := complexType.SimpleContent.Extension
+		fields = append(fields, valueField(m.toGoType(extension.Base, false)))
+		fields = append(fields, m.attributes(owner, extension.Attributes)...)
+		fields = append(fields, m.attributeWildcard(extension.AnyAttribute, "")...)
+
+	case complexType.ComplexContent.Restriction.Base != "":
+		restriction := complexType.ComplexContent.Restriction
+		fields = append(fields, m.particles(owner, &restriction.ContentModel, withAny)...)
+		fields = append(fields, m.attributes(owner, restriction.Attributes)...)
+		fields = append(fields, m.attributeWildcard(restriction.AnyAttribute, "")...)
+
+	case complexType.SimpleContent.Restriction.Base != "":
+		restriction := complexType.SimpleContent.Restriction
+		value := valueField(stripPointerFromType(m.toGoType(restriction.ValueType, false)))
+		value.Facets = facets(owner, restriction.Restriction)
+		fields = append(fields, value)
+		fields = append(fields, m.attributes(owner, restriction.Attributes)...)
+		fields = append(fields, m.attributeWildcard(restriction.AnyAttribute, "")...)
+
+	default:
+		fields = append(fields, m.particles(owner, &complexType.ContentModel, withAny)...)
+		fields = append(fields, m.attributes(owner, complexType.Attributes)...)
+		fields = append(fields, m.attributeWildcard(complexType.AnyAttribute, "")...)
+	}
+
+	return m.settle(owner, fields)
+}
+
+// mixedContent returns the fields of a complex type with mixed content: its attributes, and the text and child
+// elements it holds in document order. The child elements are read into values of the type of their declaration,
+// the ones of a mixed base type included, and get accessors named after their field when the name is free.
+func (m *typesModeler) mixedContent(t *model.Type, complexType *xsd.ComplexType) []*model.Field {
+	var fields []*model.Field
+
+	extension, restriction := complexType.ComplexContent.Extension, complexType.ComplexContent.Restriction
+	switch {
+	case extension.Base != "":
+		fields = append(fields, &model.Field{Type: m.toGoType(extension.Base, false), Embedded: true})
+		fields = append(fields, m.attributes(t.Name, extension.Attributes)...)
+		fields = append(fields, m.attributeWildcard(extension.AnyAttribute, extension.Base)...)
+	case restriction.Base != "":
+		fields = append(fields, m.attributes(t.Name, restriction.Attributes)...)
+		fields = append(fields, m.attributeWildcard(restriction.AnyAttribute, "")...)
+	default:
+		fields = append(fields, m.attributes(t.Name, complexType.Attributes)...)
+		fields = append(fields, m.attributeWildcard(complexType.AnyAttribute, "")...)
+	}
+
+	nameFields(t.Name, fields, "Content")
+
+	// the content of a mixed base type is promoted
+	if base, _, ok := m.baseComplexType(extension.Base); !ok || !isMixed(base) {
+		fields = append(fields, &model.Field{
+			Name: "Content",
+			Type: "[]xsd.MixedNode",
+			Tag:  `xml:",any" json:"content,omitempty"`,
+			Doc:  fmt.Sprintf("Content holds the text and the child elements of %s in document order.", t.Name),
+		})
+	}
+
+	t.Mixed = true
+	t.MixedElements = m.mixedElements(t.Name, complexType)
+
+	taken := make(map[string]bool)
+	for _, name := range methodNames {
+		taken[name] = true
+	}
+
+	for _, f := range t.Fields {
+		taken[f.Selector()] = true
+	}
+
+	for _, f := range fields {
+		taken[f.Selector()] = true
+	}
+
+	for _, element := range t.MixedElements {
+		if !taken[element.Accessor] {
+			taken[element.Accessor] = true
+			continue
+		}
+
+		element.Accessor = ""
+	}
+
+	return fields
+}
+
+// mixedElements returns the child elements declared by a complex type with mixed content, the ones of its mixed
+// base type first. Elements of anonymous types and members of substitution groups are left to be read as raw
+// elements.
+func (m *typesModeler) mixedElements(owner string, complexType *xsd.ComplexType) []*model.MixedElement {
+	var mixed []*model.MixedElement
+	var elements []*xsd.Element
+
+	extension, restriction := complexType.ComplexContent.Extension, complexType.ComplexContent.Restriction
+	switch {
+	case extension.Base != "":
+		if base, schema, ok := m.baseComplexType(extension.Base); ok && isMixed(base) {
+			mixed = newTypesModeler(m.b, schema).mixedElements(owner, base)
+		}
+		elements = extension.Elements()
+	case restriction.Base != "":
+		elements = restriction.Elements()
+	default:
+		elements = complexType.Elements()
+	}
+
+	declared := make(map[xml.Name]bool)
+	for _, element := range mixed {
+		declared[element.Name] = true
+	}
+
+	for _, element := range elements {
+		if element.Ref == "" && element.Type == "" && element.SimpleType == nil {
+			continue
+		}
+
+		field := m.elementValueField(owner, element, false)
+		name := tagName(field.Tag)
+		if name.Local == "" || declared[name] {
+			continue
+		}
+
+		declared[name] = true
+		mixed = append(mixed, &model.MixedElement{
+			Accessor: field.Name,
+			Name:     name,
+			Type:     strings.TrimPrefix(strings.TrimPrefix(field.Type, "[]"), "*"),
+		})
+	}
+
+	return mixed
+}
+
+// baseComplexType returns the complex type a type derives from along with the schema declaring it.
+func (m *typesModeler) baseComplexType(base string) (*xsd.ComplexType, *xsd.Schema, bool) {
+	if base == "" {
+		return nil, nil, false
+	}
+
+	complexType, _, ok := m.b.symbols.complexTypes.get(m.b.symbols.typeName(m.scope, base))
+	if !ok {
+		return nil, nil, false
+	}
+
+	return complexType, m.b.symbols.schemas[complexType], true
+}
+
+// isMixed tells whether a complex type has mixed content, the mixed attribute of its complex content overriding
+// the one of the type.
+func isMixed(complexType *xsd.ComplexType) bool {
+	if mixed := complexType.ComplexContent.Mixed; mixed != nil {
+		return *mixed
+	}
+
+	return complexType.Mixed
+}
+
+func (m *typesModeler) particles(owner string, content *xsd.ContentModel, withAny bool) []*model.Field {
+	if group := content.ModelGroup(); group != nil {
+		return m.modelGroup(owner, group, withAny, group.MinOccurs == "0")
+	}
+
+	return nil
+}
+
+// modelGroup returns the fields of the particles of a model group in document order, the elements of nested
+// model groups being fields of the same struct. A repeated group made of a single element repeats that element,
+// other repeated groups are left to settle, a field standing for each of them. The elements of optional groups
+// and the alternatives of choices are optional whatever their own minOccurs.
+func (m *typesModeler) modelGroup(owner string, group *xsd.ModelGroup, withAny, optional bool) []*model.Field {
+	var fields []*model.Field
+
+	optional = optional || group.Kind() == "choice" && len(group.Particles) > 1
+
+	for _, particle := range group.Particles {
+		switch {
+		case particle.Element != nil:
+			fields = append(fields, m.elementField(owner, particle.Element, optional))
+
+		case particle.Any != nil:
+			if withAny {
+				fields = append(fields, &model.Field{Name: "Items", Type: "[]*xsd.Node", Tag: `xml:",any" json:"items,omitempty"`})
+			}
+
+		case particle.ModelGroup != nil:
+			// the elements of an occurrence of a repeated group are there once the occurrence is
+			repeated := isRepeated(particle.ModelGroup.MaxOccurs)
+			nested := m.modelGroup(owner, particle.ModelGroup, withAny, !repeated && (optional || particle.ModelGroup.MinOccurs == "0"))
+
+			switch {
+			case !repeated:
+				fields = append(fields, nested...)
+
+			case len(particle.ModelGroup.Particles) == 1 && particle.ModelGroup.Particles[0].Element != nil:
+				fields = append(fields, repeatFields(nested)...)
+
+			case len(nested) > 0:
+				field := &model.Field{Name: makePublic(particle.ModelGroup.Kind())}
+				m.repeated[field] = &repeatedGroup{kind: particle.ModelGroup.Kind(), fields: nested}
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	return fields
+}
+
+// settle completes the fields of a struct, its repeated groups being held by slices of group structs. encoding/xml
+// only hands the elements that no field is named after to the first wildcard field of a struct, so the element
+// fields of a struct having several repeated groups or wildcards are moved, from the first of them on, to a content
+// struct handing each element to the field holding it.
+func (m *typesModeler) settle(owner string, fields []*model.Field) []*model.Field {
+	// the fields the elements no field is named after are handed to, the first one being at first
+	first, wildcards, groups := -1, 0, 0
+	for i, field := range fields {
+		_, group := m.repeated[field]
+		if !group && !isWildcard(field) {
+			continue
+		}
+
+		if first < 0 {
+			first = i
+		}
+
+		if group {
+			groups++
+		}
+		wildcards++
+	}
+
+	if groups > 0 && wildcards > 1 {
+		fields = m.groupContent(owner, fields, first)
+	}
+
+	return m.settleGroups(owner, fields)
+}
+
+// settleGroups gives the repeated groups among the fields of a struct their group structs. Fields named alike by
+// several wildcards are kept once.
+func (m *typesModeler) settleGroups(owner string, fields []*model.Field) []*model.Field {
+	var settled []*model.Field
+	names := make(map[string]bool)
+
+	for _, field := range fields {
+		group, ok := m.repeated[field]
+		delete(m.repeated, field)
+
+		switch {
+		case ok:
+			m.groupType(owner, field, group)
+
+		case field.Name == "Items" && names[field.Name] && isWildcard(field):
+			continue
+		}
+
+		names[field.Name] = true
+		settled = append(settled, field)
+	}
+
+	nameFields(owner, settled)
+
+	return settled
+}
+
+// isWildcard tells whether a field holds the elements no other field of its struct is named after.
+func isWildcard(field *model.Field) bool {
+	return strings.Contains(field.Tag, `xml:",any"`)
+}
+
+// methodNames are the names of the methods of the generated structs, which their fields cannot have.
+var methodNames = []string{"MarshalXML", "UnmarshalXML", "Validate", "XSIType", "BodyElements"}
+
+// jsonName matches the name of the json tag of a field.
+var jsonName = regexp.MustCompile(`json:"([^",]+)`)
+
+// nameFields numbers the fields of a struct sharing their name with a previous field, such as an element and an
+// attribute named alike, with an embedded field, with a method of the struct, or with one of the reserved names.
+// The names of their json tags are numbered alike, encoding/json leaving out the fields sharing one.
+func nameFields(owner string, fields []*model.Field, reserved ...string) {
+	taken, jsonNames := map[string]bool{"XMLName": true}, map[string]bool{"-": true}
+	for _, name := range append(append([]string(nil), methodNames...), reserved...) {
+		taken[name] = true
+	}
+
+	for _, f := range fields {
+		if f.Embedded {
+			taken[f.Selector()] = true
+		}
+	}
+
+	for _, f := range fields {
+		if f.Embedded {
+			continue
+		}
+
+		if name := unique(f.Name, taken); name != f.Name {
+			log.Printf("[WARN] %s is already taken in %s, generating %s", f.Name, owner, name)
+			f.Name = name
+		}
+
+		if match := jsonName.FindStringSubmatchIndex(f.Tag); match != nil && f.Tag[match[2]:match[3]] != "-" {
+			f.Tag = f.Tag[:match[2]] + unique(f.Tag[match[2]:match[3]], jsonNames) + f.Tag[match[3]:]
+		}
+	}
+}
+
+// groupType generates the group struct holding an occurrence of a repeated group, along with the slice of them
+// the field standing for the group is given.
+func (m *typesModeler) groupType(owner string, field *model.Field, group *repeatedGroup) {
+	name := m.b.symbols.reserve(owner + field.Name + "Group")
+
+	m.localTypes = append(m.localTypes,
+		&model.Type{
+			Name:   name,
+			Doc:    fmt.Sprintf("%s is an occurrence of a repeated %s of %s.", name, group.kind, owner),
+			Fields: m.settleGroups(name, group.fields),
+		},
+		&model.Type{Name: name + "s", Underlying: "[]*" + name, ModelGroup: true},
+	)
+
+	field.Type = name + "s"
+	field.Tag = fmt.Sprintf(`xml:",any" json:"%s,omitempty"`, group.kind)
+}
+
+// groupContent moves the element fields of a struct from the field first on to a content struct, which reads and
+// writes them in document order, the struct being given a field holding its content instead.
+func (m *typesModeler) groupContent(owner string, fields []*model.Field, first int) []*model.Field {
+	name := m.b.symbols.reserve(owner + "Content")
+	content := &model.Field{Name: "Content", Type: "*" + name, Tag: `xml:",any" json:"content,omitempty"`}
+
+	var elements []*model.Field
+	kept := append(fields[:first:first], content)
+	for _, field := range fields[first:] {
+		if strings.Contains(field.Tag, ",attr") {
+			kept = append(kept, field)
+			continue
+		}
+
+		elements = append(elements, field)
+	}
+
+	m.localTypes = append(m.localTypes, &model.Type{
+		Name:         name,
+		Doc:          fmt.Sprintf("%s holds the elements of %s from its first repeated group or wildcard on, in document order.", name, owner),
+		Fields:       m.settleGroups(owner, elements),
+		GroupContent: true,
+	})
+
+	return kept
+}
+
+func (m *typesModeler) takeLocalTypes() []*model.Type {
+	types := m.localTypes
+	m.localTypes = nil
+
+	return types
+}
+
+// elementField returns the field of an element along with the constraints Validate checks on it, optional telling
+// whether the model group holding it makes it optional.
+func (m *typesModeler) elementField(owner string, element *xsd.Element, optional bool) *model.Field {
+	field := m.elementValueField(owner, element, optional)
+	optional = optional || element.MinOccurs == "0"
+
+	if isRepeated(element.MaxOccurs) {
+		occurs := &model.Occurs{Min: 1, Max: -1}
+		if n, err := strconv.Atoi(element.MinOccurs); err == nil {
+			occurs.Min = n
+		}
+
+		if n, err := strconv.Atoi(element.MaxOccurs); err == nil {
+			occurs.Max = n
+		}
+
+		if optional {
+			occurs.Min = 0
+		}
+
+		if occurs.Min > 0 || occurs.Max >= 0 {
+			field.Occurs = occurs
+		}
+	} else {
+		// only the fields which may be left unset can miss a required element
+		field.Required = !optional && (strings.HasPrefix(field.Type, "*") || strings.HasPrefix(field.Type, "xsd.Nillable["))
+	}
+
+	if element.Ref == "" && element.Type == "" && element.SimpleType != nil {
+		field.Facets = facets(owner+"."+field.Name, element.SimpleType.Restriction)
+	}
+
+	defaultValue, fixed := element.Default, element.Fixed
+	if element.Ref != "" && defaultValue == "" && fixed == "" {
+		if target, _, ok := m.b.symbols.elements.get(m.b.symbols.elementName(m.scope, element.Ref)); ok {
+			defaultValue, fixed = target.Default, target.Fixed
+		}
+	}
+
+	if !isRepeated(element.MaxOccurs) && field.Type != "" {
+		field.Preset = m.preset(owner, field, defaultValue, fixed)
+	}
+
+	return field
+}
+
+// elementValueField returns the field of an element. Repeated elements are held by slices, optional ones by
+// pointers so that they are only written when set, and nillable ones by xsd.Nillable.
+func (m *typesModeler) elementValueField(owner string, element *xsd.Element, optional bool) *model.Field {
+	repeated := isRepeated(element.MaxOccurs)
+	optional = optional || element.MinOccurs == "0"
+
+	if element.Ref != "" {
+		ref := m.b.symbols.elementName(m.scope, element.Ref)
+		field := &model.Field{Name: m.b.fieldName(ref.Local)}
+
+		if m.b.isSubstitutionGroupHead(ref) {
+			field.Type = occurrenceType("*"+derivedName("%sChoice", m.b.goElementName(ref)), repeated, optional)
+			field.Tag = fmt.Sprintf(`xml:",any" json:"%s,omitempty"`, stripAliasNSFromType(element.Ref))
+		} else {
+			goType := m.b.symbols.goElementType(m.scope, element.Ref)
+			if target, _, ok := m.b.symbols.elements.get(ref); ok && target.Nillable {
+				goType = nillableType(goType)
+			}
+
+			field.Type = occurrenceType(goType, repeated, optional)
+			field.Tag = elementTag(qualifiedTagName(ref), ref.Local, optional)
+		}
+
+		return field
+	}
+
+	switch {
+	case element.Type != "":
+		goType := m.toGoElementType(element.Type, false)
+		if element.Nillable {
+			goType = nillableType(goType)
+		}
+
+		return &model.Field{
+			Name: m.b.fieldName(element.Name),
+			Type: occurrenceType(goType, repeated, optional),
+			Tag:  elementTag(m.elementTagName(element), element.Name, optional),
+			Doc:  element.Doc,
+		}
+
+	case element.SimpleType != nil:
+		field := &model.Field{Name: m.b.fieldName(element.Name), Tag: elementTag(m.elementTagName(element), element.Name, optional), Doc: element.Doc}
+		goType := m.toGoType(element.SimpleType.Restriction.Base, false)
+		if element.SimpleType.List.ItemType != "" || element.SimpleType.List.SimpleType != nil {
+			goType = "xsd.ListOf[" + m.listItem(element.SimpleType.List) + "]"
+		}
+
+		if element.Nillable {
+			goType = nillableType(goType)
+		}
+
+		field.Type = occurrenceType(goType, repeated, optional)
+
+		return field
+	}
+
+	field := &model.Field{Name: m.b.fieldName(element.Name), Tag: elementTag(m.elementTagName(element), element.Name, optional), Slice: repeated}
+	if element.ComplexType != nil {
+		field.Struct = m.content(owner+field.Name, element.ComplexType, false)
+	}
+
+	return field
+}
+
+// elementTagName returns the name a local element is written with, qualified by the target namespace of the schema
+// declaring it when its form or the schema default says so.
+func (m *typesModeler) elementTagName(element *xsd.Element) string {
+	namespace := element.Namespace
+	if namespace == "" {
+		namespace = m.schema.TargetNamespace
+	}
+
+	if !m.schema.QualifiesElement(element.Form) {
+		m.b.unqualifiedElements = m.b.unqualifiedElements || namespace != ""
+		return element.Name
+	}
+
+	return qualifiedTagName(xml.Name{Space: namespace, Local: element.Name})
+}
+
+// occurrenceType returns the type of the field holding an element of type goType: a slice when the element is
+// repeated, a pointer when it is optional and the zero value of its type could not be told apart from its absence.
+func occurrenceType(goType string, repeated, optional bool) string {
+	switch {
+	case repeated:
+		return "[]" + goType
+	case optional && !strings.HasPrefix(goType, "*") && !strings.HasPrefix(goType, "[]") &&
+		!strings.HasPrefix(goType, "xsd.Nillable[") && !strings.HasPrefix(goType, "xsd.ListOf[") && goType != "interface{}":
+		return "*" + goType
+	}
+
+	return goType
+}
+
+// nillableType returns the type holding a nillable element of type goType, which tells absent, nil and valued
+// elements apart.
+func nillableType(goType string) string {
+	return "xsd.Nillable[" + goType + "]"
+}
+
+// attributes returns the fields of the attributes of a complex type, owner being the struct holding them. Attributes
+// having a fixed value of a basic type get a type of their own writing that value when they are left empty.
+func (m *typesModeler) attributes(owner string, attributes []*xsd.Attribute) []*model.Field {
+	var fields []*model.Field
+
+	for _, attr := range attributes {
+		if attr.Use == "prohibited" {
+			continue
+		}
+
+		field := &model.Field{
+			Name:     m.b.fieldName(attr.Name),
+			Type:     "string",
+			Tag:      fmt.Sprintf(`xml:"%s,attr,omitempty" json:"%s,omitempty"`, attributeTagName(attr), attr.Name),
+			Doc:      attr.Doc,
+			Required: attr.Use == "required",
+		}
+
+		if attr.SimpleType != nil {
+			field.Facets = facets(owner+"."+field.Name, attr.SimpleType.Restriction)
+		}
+
+		switch {
+		case attr.Type != "":
+			field.Type = m.toGoType(attr.Type, false)
+		case attr.SimpleType != nil && (attr.SimpleType.List.ItemType != "" || attr.SimpleType.List.SimpleType != nil):
+			field.Type = "xsd.ListOf[" + m.listItem(attr.SimpleType.List) + "]"
+		}
+
+		field.Preset = m.preset(owner, field, attr.Default, attr.Fixed)
+		if field.Preset != nil && field.Preset.Fixed && isBasicType(field.Type) && !strings.ContainsAny(field.Type, "[{.") {
+			name := m.b.symbols.reserve(owner + field.Name)
+			m.localTypes = append(m.localTypes, &model.Type{
+				Name:       name,
+				Doc:        fmt.Sprintf("%s is the %s attribute of %s, written with its fixed value when left empty.", name, attr.Name, owner),
+				Underlying: field.Type,
+				FixedValue: field.Preset.Constant,
+			})
+
+			field.Type = name
+			field.Tag = fmt.Sprintf(`xml:"%s,attr" json:"%s,omitempty"`, attributeTagName(attr), attr.Name)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// attributeWildcard returns the field holding the attributes an xs:anyAttribute allows, unless the complex type
+// it extends already has one, which is promoted.
+func (m *typesModeler) attributeWildcard(wildcard *xsd.AnyAttribute, base string) []*model.Field {
+	if wildcard == nil || m.inheritsAttributeWildcard(base) {
+		return nil
+	}
+
+	return []*model.Field{{Name: "AnyAttrs", Type: "[]xml.Attr", Tag: `xml:",any,attr" json:"anyAttrs,omitempty"`}}
+}
+
+// inheritsAttributeWildcard tells whether a complex type extended by complex content, or one of its ancestors, has
+// an attribute wildcard.
+func (m *typesModeler) inheritsAttributeWildcard(base string) bool {
+	complexType, schema, ok := m.baseComplexType(base)
+	if !ok {
+		return false
+	}
+
+	switch extension := complexType.ComplexContent.Extension; {
+	case extension.Base != "":
+		return extension.AnyAttribute != nil || newTypesModeler(m.b, schema).inheritsAttributeWildcard(extension.Base)
+	case complexType.ComplexContent.Restriction.Base != "":
+		return complexType.ComplexContent.Restriction.AnyAttribute != nil
+	case complexType.SimpleContent.Extension.Base != "":
+		return complexType.SimpleContent.Extension.AnyAttribute != nil
+	case complexType.SimpleContent.Restriction.Base != "":
+		return complexType.SimpleContent.Restriction.AnyAttribute != nil
+	}
+
+	return complexType.AnyAttribute != nil
+}
+
+// preset returns the preset of the field of an element or attribute having a default or fixed value, the
+// constant holding the value being named after the field. A value the constructor can't set the field to is
+// warned about.
+func (m *typesModeler) preset(owner string, field *model.Field, defaultValue, fixed string) *model.Preset {
+	var preset *model.Preset
+
+	switch {
+	case fixed != "":
+		preset = &model.Preset{Constant: m.b.symbols.reserve(owner + field.Name + "Fixed"), Value: fixed, Fixed: true}
+	case defaultValue != "":
+		preset = &model.Preset{Constant: m.b.symbols.reserve(owner + field.Name + "Default"), Value: defaultValue}
+	default:
+		return nil
+	}
+
+	if err := checkPreset(field.Type, preset.Value); err != nil {
+		kind := "Default"
+		if preset.Fixed {
+			kind = "Fixed"
+		}
+
+		log.Printf("[WARN] %s value %q of %s.%s is not a valid %s, leaving the field unset: %s", kind, preset.Value,
+			owner, field.Name, field.Type, err)
+	}
+
+	return preset
+}
+
+// presetTypes are the Go types of the fields whose default and fixed values are checked, by name.
+var presetTypes = map[string]reflect.Type{
+	"string":       reflect.TypeOf(""),
+	"bool":         reflect.TypeOf(false),
+	"int":          reflect.TypeOf(int(0)),
+	"int8":         reflect.TypeOf(int8(0)),
+	"int16":        reflect.TypeOf(int16(0)),
+	"int32":        reflect.TypeOf(int32(0)),
+	"int64":        reflect.TypeOf(int64(0)),
+	"uint":         reflect.TypeOf(uint(0)),
+	"byte":         reflect.TypeOf(byte(0)),
+	"uint8":        reflect.TypeOf(uint8(0)),
+	"uint16":       reflect.TypeOf(uint16(0)),
+	"uint32":       reflect.TypeOf(uint32(0)),
+	"uint64":       reflect.TypeOf(uint64(0)),
+	"float32":      reflect.TypeOf(float32(0)),
+	"float64":      reflect.TypeOf(float64(0)),
+	"xsd.DateTime": reflect.TypeOf(xsd.DateTime{}),
+	"xsd.Date":     reflect.TypeOf(xsd.Date{}),
+	"xsd.Time":     reflect.TypeOf(xsd.Time{}),
+}
+
+// checkPreset parses a default or fixed value the way the generated constructor does, for the fields held by a
+// basic or xsd date and time type, optional or nillable ones included.
+func checkPreset(goType, value string) error {
+	goType = strings.TrimPrefix(goType, "*")
+	if strings.HasPrefix(goType, "xsd.Nillable[") {
+		goType = strings.TrimSuffix(strings.TrimPrefix(goType, "xsd.Nillable["), "]")
+	}
+
+	t, ok := presetTypes[goType]
+	if !ok {
+		return nil
+	}
+
+	return xsd.ParseValue(reflect.New(t).Interface(), value)
+}
+
+// presetConstants returns the constants holding the default and fixed values of the fields of a struct, the ones of
+// the anonymous structs it holds included.
+func presetConstants(typeName, path string, fields []*model.Field) []*model.Constant {
+	var constants []*model.Constant
+
+	for _, f := range fields {
+		if p := f.Preset; p != nil {
+			kind := "default"
+			if p.Fixed {
+				kind = "fixed"
+			}
+
+			constants = append(constants, &model.Constant{
+				Name:  p.Constant,
+				Value: p.Value,
+				Doc:   fmt.Sprintf("%s is the %s value of %s.%s.", p.Constant, kind, typeName, path+f.Name),
+			})
+		}
+
+		constants = append(constants, presetConstants(typeName, path+f.Name+".", f.Struct)...)
+	}
+
+	return constants
+}
+
+// repeatFields returns copies of fields holding several elements where they held one.
+func repeatFields(fields []*model.Field) []*model.Field {
+	repeated := make([]*model.Field, 0, len(fields))
+
+	for _, field := range fields {
+		f := *field
+		f.Required, f.Occurs, f.Preset = false, nil, nil
+
+		switch {
+		case f.Type == "":
+			f.Slice = true
+		case !strings.HasPrefix(f.Type, "[]"):
+			f.Type = "[]" + f.Type
+		}
+
+		repeated = append(repeated, &f)
+	}
+
+	return repeated
+}
+
+// isRepeated tells whether a particle may occur several times.
+func isRepeated(maxOccurs string) bool {
+	if maxOccurs == "unbounded" {
+		return true
+	}
+
+	n, err := strconv.Atoi(maxOccurs)
+	return err == nil && n > 1
+}
+
+func xmlNameField(name string) *model.Field {
+	return &model.Field{Name: "XMLName", Type: "xml.Name", Tag: fmt.Sprintf(`xml:"%s"`, name)}
+}
+
+func valueField(goType string) *model.Field {
+	return &model.Field{Name: "Value", Type: goType, Tag: `xml:",chardata" json:"-,"`}
+}
+
+// tagName returns the name an element field is written with, which wildcard fields do not have.
+func tagName(tag string) xml.Name {
+	value := reflect.StructTag(tag).Get("xml")
+	name := value[:strings.Index(value+",", ",")]
+
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		return xml.Name{Space: name[:i], Local: name[i+1:]}
+	}
+
+	return xml.Name{Local: name}
+}
+
+// qualifiedTagName returns the name of a tag, prefixed by its namespace when it has one.
+func qualifiedTagName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + " " + name.Local
+}
+
+// attributeTagName returns the name an attribute is written with, qualified by its namespace when it has one.
+func attributeTagName(attr *xsd.Attribute) string {
+	return qualifiedTagName(xml.Name{Space: attr.Namespace, Local: attr.Name})
+}
+
+// tag returns the tag of an optional element field.
+func tag(xmlName, jsonName string) string {
+	return fmt.Sprintf(`xml:"%s,omitempty" json:"%s,omitempty"`, xmlName, jsonName)
+}
+
+// elementTag returns the tag of an element field, required elements being written even when empty.
+func elementTag(xmlName, jsonName string, optional bool) string {
+	if optional {
+		return tag(xmlName, jsonName)
+	}
+
+	return fmt.Sprintf(`xml:"%s" json:"%s"`, xmlName, jsonName)
+}
+
+func containsUnionMember(members []*model.UnionMember, name string) bool {
+	for _, member := range members {
+		if member.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,9 +2,18 @@ package wsdl
 
 // Binding defines only a SOAP binding and its operations
 type Binding struct {
-	Name        string       `xml:"name,attr"`
-	Type        string       `xml:"type,attr"`
-	Doc         string       `xml:"documentation"`
-	SOAPBinding SOAPBinding  `xml:"http://schemas.xmlsoap.org/wsdl/soap/ binding"`
-	Operations  []*Operation `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
+	// Namespace is the target namespace of the document declaring the binding, which qualifies its name.
+	Namespace string `xml:"-"`
+
+	Name          string       `xml:"name,attr"`
+	Type          string       `xml:"type,attr"`
+	Doc           string       `xml:"documentation"`
+	SOAPBinding   SOAPBinding  `xml:"http://schemas.xmlsoap.org/wsdl/soap/ binding"`
+	SOAP12Binding *SOAPBinding `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ binding"`
+	Operations    []*Operation `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
+}
+
+// IsSOAP12 reports whether the binding is a SOAP 1.2 binding.
+func (b *Binding) IsSOAP12() bool {
+	return b.SOAP12Binding != nil
 }
@@ -2,9 +2,11 @@ package wsdl
 
 // Input represents input message.
 type Input struct {
-	Name       string        `xml:"name,attr"`
-	Message    string        `xml:"message,attr"`
-	Doc        string        `xml:"documentation"`
-	SOAPBody   SOAPBody      `xml:"http://schemas.xmlsoap.org/wsdl/soap/ body"`
-	SOAPHeader []*SOAPHeader `xml:"http://schemas.xmlsoap.org/wsdl/soap/ header"`
+	Name         string        `xml:"name,attr"`
+	Message      string        `xml:"message,attr"`
+	Doc          string        `xml:"documentation"`
+	SOAPBody     SOAPBody      `xml:"http://schemas.xmlsoap.org/wsdl/soap/ body"`
+	SOAPHeader   []*SOAPHeader `xml:"http://schemas.xmlsoap.org/wsdl/soap/ header"`
+	SOAP12Body   SOAPBody      `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ body"`
+	SOAP12Header []*SOAPHeader `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ header"`
 }
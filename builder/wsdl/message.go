@@ -2,6 +2,9 @@ package wsdl
 
 // Message represents a function, which in turn has one or more parameters.
 type Message struct {
+	// Namespace is the target namespace of the document declaring the message, which qualifies its name.
+	Namespace string `xml:"-"`
+
 	Name  string  `xml:"name,attr"`
 	Doc   string  `xml:"documentation"`
 	Parts []*Part `xml:"http://schemas.xmlsoap.org/wsdl/ part"`
@@ -2,10 +2,15 @@ package wsdl
 
 // Operation represents the contract of an entire operation or function.
 type Operation struct {
-	Name          string        `xml:"name,attr"`
-	Doc           string        `xml:"documentation"`
-	Input         Input         `xml:"input"`
-	Output        Output        `xml:"output"`
-	Faults        []*Fault      `xml:"fault"`
-	SOAPOperation SOAPOperation `xml:"http://schemas.xmlsoap.org/wsdl/soap/ operation"`
+	Name            string        `xml:"name,attr"`
+	Doc             string        `xml:"documentation"`
+	Input           Input         `xml:"input"`
+	Output          Output        `xml:"output"`
+	Faults          []*Fault      `xml:"fault"`
+	SOAPOperation   SOAPOperation `xml:"http://schemas.xmlsoap.org/wsdl/soap/ operation"`
+	SOAP12Operation SOAPOperation `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ operation"`
+
+	// OptionalOutput is set on the operations of WSDL 2.0 descriptions following the in-optional-out pattern, which
+	// the service may answer with nothing.
+	OptionalOutput bool `xml:"-"`
 }
@@ -3,6 +3,9 @@ package wsdl
 // PortType defines the service, operations that can be performed and the messages involved.
 // A port type can be compared to a function library, module or class.
 type PortType struct {
+	// Namespace is the target namespace of the document declaring the port type, which qualifies its name.
+	Namespace string `xml:"-"`
+
 	Name       string       `xml:"name,attr"`
 	Doc        string       `xml:"documentation"`
 	Operations []*Operation `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
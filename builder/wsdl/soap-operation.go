@@ -2,6 +2,7 @@ package wsdl
 
 // SOAPOperation represents a service operation in SOAP terms.
 type SOAPOperation struct {
-	SOAPAction string `xml:"soapAction,attr"`
-	Style      string `xml:"style,attr"`
+	SOAPAction         string `xml:"soapAction,attr"`
+	SOAPActionRequired string `xml:"soapActionRequired,attr"`
+	Style              string `xml:"style,attr"`
 }
@@ -2,6 +2,9 @@ package wsdl
 
 import (
 	"encoding/xml"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 const wsdlNamespace = "http://schemas.xmlsoap.org/wsdl/"
@@ -30,6 +33,11 @@ func (w *WSDL) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			continue
 		}
 
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			w.Xmlns[""] = attr.Value
+			continue
+		}
+
 		switch attr.Name.Local {
 		case "name":
 			w.Name = attr.Value
@@ -69,16 +77,17 @@ Loop:
 					if err := d.DecodeElement(&w.Types, &t); err != nil {
 						return err
 					}
+					// schemas inherit the prefixes of the definitions, not their default namespace
 					for prefix, namespace := range w.Xmlns {
 						for _, s := range w.Types.Schemas {
-							if _, ok := s.Xmlns[prefix]; !ok {
+							if _, ok := s.Xmlns[prefix]; !ok && prefix != "" {
 								s.Xmlns[prefix] = namespace
 							}
 						}
 					}
 
 				case "message":
-					x := new(Message)
+					x := &Message{Namespace: w.TargetNamespace}
 					if err := d.DecodeElement(x, &t); err != nil {
 						return err
 					}
@@ -86,7 +95,7 @@ Loop:
 					w.Messages = append(w.Messages, x)
 
 				case "portType":
-					x := new(PortType)
+					x := &PortType{Namespace: w.TargetNamespace}
 					if err := d.DecodeElement(x, &t); err != nil {
 						return err
 					}
@@ -94,7 +103,7 @@ Loop:
 					w.PortTypes = append(w.PortTypes, x)
 
 				case "binding":
-					x := new(Binding)
+					x := &Binding{Namespace: w.TargetNamespace}
 					if err := d.DecodeElement(x, &t); err != nil {
 						return err
 					}
@@ -133,3 +142,135 @@ Loop:
 
 	return nil
 }
+
+// Merge adds the definitions of an imported WSDL document. The names its message parts, operations, bindings and
+// ports refer to are rewritten with the prefixes of the importing document, which declares the namespaces it has no
+// prefix for, as both documents may bind a prefix to different namespaces. Its target namespace is only used when the importing document has none.
+// Schemas are merged by target namespace: the components already declared for a namespace, as happens when
+// several documents embed the same schema, are left out.
+func (w *WSDL) Merge(imported *WSDL) {
+	if w.TargetNamespace == "" {
+		w.TargetNamespace = imported.TargetNamespace
+	}
+
+	for _, schema := range imported.Types.Schemas {
+		for _, existing := range w.Types.Schemas {
+			if existing.TargetNamespace == schema.TargetNamespace {
+				schema.Without(existing)
+			}
+		}
+
+		w.Types.Schemas = append(w.Types.Schemas, schema)
+	}
+
+	for _, message := range imported.Messages {
+		for _, part := range message.Parts {
+			part.Element = w.requalify(part.Element, imported)
+			part.Type = w.requalify(part.Type, imported)
+		}
+	}
+
+	for _, portType := range imported.PortTypes {
+		for _, operation := range portType.Operations {
+			w.requalifyOperation(operation, imported)
+		}
+	}
+
+	for _, binding := range imported.Binding {
+		binding.Type = w.requalify(binding.Type, imported)
+		for _, operation := range binding.Operations {
+			w.requalifyOperation(operation, imported)
+		}
+	}
+
+	for _, service := range imported.Service {
+		for _, port := range service.Ports {
+			port.Binding = w.requalify(port.Binding, imported)
+		}
+	}
+
+	w.Messages = append(w.Messages, imported.Messages...)
+	w.PortTypes = append(w.PortTypes, imported.PortTypes...)
+	w.Binding = append(w.Binding, imported.Binding...)
+	w.Service = append(w.Service, imported.Service...)
+}
+
+// requalifyOperation rewrites the names of the messages an operation of a port type or of a binding refers to, the
+// messages of its headers included.
+func (w *WSDL) requalifyOperation(operation *Operation, from *WSDL) {
+	operation.Input.Message = w.requalify(operation.Input.Message, from)
+	operation.Output.Message = w.requalify(operation.Output.Message, from)
+	for _, fault := range operation.Faults {
+		fault.Message = w.requalify(fault.Message, from)
+	}
+
+	for _, headers := range [][]*SOAPHeader{operation.Input.SOAPHeader, operation.Input.SOAP12Header, operation.Output.SOAPHeader, operation.Output.SOAP12Header} {
+		for _, header := range headers {
+			header.Message = w.requalify(header.Message, from)
+			for _, fault := range header.HeadersFault {
+				fault.Message = w.requalify(fault.Message, from)
+			}
+		}
+	}
+}
+
+// requalify rewrites a prefixed name used in the document from so that it keeps its meaning in the document, the
+// namespace being declared under a new prefix when the document has no prefix for it.
+func (w *WSDL) requalify(name string, from *WSDL) string {
+	if name == "" {
+		return name
+	}
+
+	prefix, local := "", name
+	if i := strings.Index(name, ":"); i >= 0 {
+		prefix, local = name[:i], name[i+1:]
+	}
+
+	space, ok := from.Xmlns[prefix]
+	if !ok {
+		if prefix != "" {
+			return name
+		}
+		space = from.TargetNamespace
+	}
+
+	return w.Qualify(xml.Name{Space: space, Local: local})
+}
+
+// Qualify returns the prefixed name a qualified name is written as in the document, the namespace being declared
+// under a new prefix when the document has no prefix for it. The first prefix in sorted order is used when the
+// document binds several of them to the namespace.
+func (w *WSDL) Qualify(name xml.Name) string {
+	space, local := name.Space, name.Local
+
+	prefixes := make([]string, 0, len(w.Xmlns))
+	for prefix := range w.Xmlns {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, prefix := range prefixes {
+		if w.Xmlns[prefix] == space {
+			if prefix == "" {
+				return local
+			}
+			return prefix + ":" + local
+		}
+	}
+
+	if _, ok := w.Xmlns[""]; !ok && space == w.TargetNamespace {
+		return local
+	}
+
+	if w.Xmlns == nil {
+		w.Xmlns = make(map[string]string)
+	}
+
+	prefix := "ns1"
+	for i := 2; w.Xmlns[prefix] != ""; i++ {
+		prefix = "ns" + strconv.Itoa(i)
+	}
+
+	w.Xmlns[prefix] = space
+	return prefix + ":" + local
+}
@@ -0,0 +1,7 @@
+package wsdl2
+
+// BindingOperation holds the SOAP details of an interface operation.
+type BindingOperation struct {
+	Ref    string `xml:"ref,attr"`
+	Action string `xml:"http://www.w3.org/ns/wsdl/soap action,attr"`
+}
@@ -0,0 +1,23 @@
+package wsdl2
+
+// Binding defines the protocol used for the operations of an interface, only the SOAP binding extension is supported.
+type Binding struct {
+	Name       string              `xml:"name,attr"`
+	Interface  string              `xml:"interface,attr"`
+	Type       string              `xml:"type,attr"`
+	Version    string              `xml:"http://www.w3.org/ns/wsdl/soap version,attr"`
+	Protocol   string              `xml:"http://www.w3.org/ns/wsdl/soap protocol,attr"`
+	Doc        string              `xml:"documentation"`
+	Operations []*BindingOperation `xml:"http://www.w3.org/ns/wsdl operation"`
+}
+
+// findAction returns the SOAP action bound to an operation of the interface.
+func (b *Binding) findAction(operation string) string {
+	for _, op := range b.Operations {
+		if localName(op.Ref) == operation {
+			return op.Action
+		}
+	}
+
+	return ""
+}
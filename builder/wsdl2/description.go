@@ -0,0 +1,275 @@
+package wsdl2
+
+import (
+	"bytes"
+	"encoding/xml"
+	"log"
+	"strings"
+
+	"github.com/go-aegian/gowsdlsoap/builder/wsdl"
+)
+
+const (
+	// Namespace is the namespace of WSDL 2.0 descriptions.
+	Namespace = "http://www.w3.org/ns/wsdl"
+	// SOAPNamespace is the namespace of the WSDL 2.0 SOAP binding extension.
+	SOAPNamespace = "http://www.w3.org/ns/wsdl/soap"
+)
+
+// Message exchange patterns supported by the generated clients.
+const (
+	PatternInOut         = "http://www.w3.org/ns/wsdl/in-out"
+	PatternInOptionalOut = "http://www.w3.org/ns/wsdl/in-optional-out"
+	PatternInOnly        = "http://www.w3.org/ns/wsdl/in-only"
+	PatternRobustInOnly  = "http://www.w3.org/ns/wsdl/robust-in-only"
+)
+
+// Description represents the global structure of a WSDL 2.0 document.
+type Description struct {
+	Xmlns           map[string]string `xml:"-"`
+	TargetNamespace string            `xml:"targetNamespace,attr"`
+	Doc             string            `xml:"documentation"`
+	Imports         []*wsdl.Import    `xml:"http://www.w3.org/ns/wsdl import"`
+	Includes        []*wsdl.Import    `xml:"http://www.w3.org/ns/wsdl include"`
+	Types           wsdl.Type         `xml:"http://www.w3.org/ns/wsdl types"`
+	Interfaces      []*Interface      `xml:"http://www.w3.org/ns/wsdl interface"`
+	Bindings        []*Binding        `xml:"http://www.w3.org/ns/wsdl binding"`
+	Services        []*Service        `xml:"http://www.w3.org/ns/wsdl service"`
+}
+
+// IsDescription reports whether a document is a WSDL 2.0 description rather than WSDL 1.1 definitions.
+func IsDescription(data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Space == Namespace && start.Name.Local == "description"
+		}
+	}
+}
+
+// UnmarshalXML implements interface xml.Unmarshaler for Description, keeping the namespace prefixes in scope.
+func (d *Description) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	type description Description
+	if err := decoder.DecodeElement((*description)(d), &start); err != nil {
+		return err
+	}
+
+	d.Xmlns = make(map[string]string)
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" {
+			d.Xmlns[attr.Name.Local] = attr.Value
+		}
+
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			d.Xmlns[""] = attr.Value
+		}
+	}
+
+	// schemas inherit the prefixes of the description, not its default namespace
+	for prefix, namespace := range d.Xmlns {
+		for _, s := range d.Types.Schemas {
+			if _, ok := s.Xmlns[prefix]; !ok && prefix != "" {
+				s.Xmlns[prefix] = namespace
+			}
+		}
+	}
+
+	return nil
+}
+
+// WSDL maps the description onto the WSDL 1.1 model: interfaces become port types whose operations refer to
+// synthesized messages, SOAP bindings and endpoints become SOAP bindings and ports.
+func (d *Description) WSDL() *wsdl.WSDL {
+	w := &wsdl.WSDL{
+		Xmlns:           d.Xmlns,
+		TargetNamespace: d.TargetNamespace,
+		Doc:             d.Doc,
+		Types:           d.Types,
+	}
+
+	w.Imports = append(append(w.Imports, d.Imports...), d.Includes...)
+
+	messages := make(map[string]bool)
+	addMessage := func(name, element string) string {
+		if !messages[name] {
+			messages[name] = true
+
+			message := &wsdl.Message{Namespace: d.TargetNamespace, Name: name}
+			if element != "" {
+				message.Parts = []*wsdl.Part{{Name: "parameters", Element: element}}
+			}
+
+			w.Messages = append(w.Messages, message)
+		}
+
+		return w.Qualify(xml.Name{Space: d.TargetNamespace, Local: name})
+	}
+
+	for _, iface := range d.Interfaces {
+		portType := &wsdl.PortType{Namespace: d.TargetNamespace, Name: iface.Name, Doc: iface.Doc}
+
+		for _, op := range d.operations(iface) {
+			operation := &wsdl.Operation{Name: op.Name, Doc: op.Doc}
+
+			switch op.pattern() {
+			case PatternInOut, PatternInOptionalOut:
+				operation.Input.Message = addMessage(op.Name+"Request", op.inputElement())
+				operation.Output.Message = addMessage(op.Name+"Response", op.outputElement())
+				operation.Faults = d.faults(iface, op.OutFaults, addMessage)
+				operation.OptionalOutput = op.pattern() == PatternInOptionalOut
+
+			case PatternRobustInOnly:
+				operation.Input.Message = addMessage(op.Name+"Request", op.inputElement())
+				operation.Faults = d.faults(iface, op.OutFaults, addMessage)
+
+			case PatternInOnly:
+				operation.Input.Message = addMessage(op.Name+"Request", op.inputElement())
+
+			default:
+				log.Printf("[WARN] %s operation uses the unsupported %s message exchange pattern, ignoring operation...", op.Name, op.pattern())
+				continue
+			}
+
+			portType.Operations = append(portType.Operations, operation)
+		}
+
+		w.PortTypes = append(w.PortTypes, portType)
+	}
+
+	soap12 := make(map[string]bool)
+
+	for _, binding := range d.Bindings {
+		if binding.Type != SOAPNamespace {
+			log.Printf("[WARN] %s binding is not a SOAP binding, ignoring binding...", binding.Name)
+			continue
+		}
+
+		b := &wsdl.Binding{Namespace: d.TargetNamespace, Name: binding.Name, Type: binding.Interface, Doc: binding.Doc}
+		soapBinding := wsdl.SOAPBinding{Style: "document", Transport: binding.Protocol}
+
+		isSOAP12 := binding.Version != "1.1"
+		if isSOAP12 {
+			b.SOAP12Binding = &soapBinding
+			soap12[binding.Name] = true
+		} else {
+			b.SOAPBinding = soapBinding
+		}
+
+		if iface := d.findInterface(binding.Interface); iface != nil {
+			for _, op := range d.operations(iface) {
+				operation := &wsdl.Operation{Name: op.Name}
+				soapOperation := wsdl.SOAPOperation{SOAPAction: binding.findAction(op.Name), Style: "document"}
+
+				if isSOAP12 {
+					operation.SOAP12Operation = soapOperation
+				} else {
+					operation.SOAPOperation = soapOperation
+				}
+
+				b.Operations = append(b.Operations, operation)
+			}
+		}
+
+		w.Binding = append(w.Binding, b)
+	}
+
+	for _, service := range d.Services {
+		s := &wsdl.Service{Name: service.Name, Doc: service.Doc}
+
+		for _, endpoint := range service.Endpoints {
+			port := &wsdl.Port{Name: endpoint.Name, Binding: endpoint.Binding, Doc: endpoint.Doc}
+			if soap12[localName(endpoint.Binding)] {
+				port.SOAP12Address.Location = endpoint.Address
+			} else {
+				port.SOAPAddress.Location = endpoint.Address
+			}
+
+			s.Ports = append(s.Ports, port)
+		}
+
+		w.Service = append(w.Service, s)
+	}
+
+	return w
+}
+
+func (d *Description) findInterface(name string) *Interface {
+	for _, iface := range d.Interfaces {
+		if iface.Name == localName(name) {
+			return iface
+		}
+	}
+
+	return nil
+}
+
+// inherited returns an interface followed by all the interfaces it extends, directly or not.
+func (d *Description) inherited(iface *Interface) []*Interface {
+	interfaces := []*Interface{iface}
+
+	for i := 0; i < len(interfaces); i++ {
+	Extends:
+		for _, name := range strings.Fields(interfaces[i].Extends) {
+			parent := d.findInterface(name)
+			if parent == nil {
+				continue
+			}
+
+			for _, known := range interfaces {
+				if known == parent {
+					continue Extends
+				}
+			}
+
+			interfaces = append(interfaces, parent)
+		}
+	}
+
+	return interfaces
+}
+
+// operations returns the operations of an interface, including the ones inherited from the interfaces it extends.
+func (d *Description) operations(iface *Interface) []*InterfaceOperation {
+	var operations []*InterfaceOperation
+
+	for _, i := range d.inherited(iface) {
+		operations = append(operations, i.Operations...)
+	}
+
+	return operations
+}
+
+// faults maps the fault references of an operation onto faults referring to the message of the interface fault.
+func (d *Description) faults(iface *Interface, references []*FaultReference, addMessage func(name, element string) string) []*wsdl.Fault {
+	var faults []*wsdl.Fault
+
+	for _, reference := range references {
+		for _, i := range d.inherited(iface) {
+			for _, fault := range i.Faults {
+				if fault.Name == localName(reference.Ref) {
+					faults = append(faults, &wsdl.Fault{
+						Name:    fault.Name,
+						Message: addMessage(fault.Name+"Fault", fault.Element),
+						Doc:     fault.Doc,
+					})
+				}
+			}
+		}
+	}
+
+	return faults
+}
+
+func localName(qname string) string {
+	if i := strings.Index(qname, ":"); i >= 0 {
+		return qname[i+1:]
+	}
+
+	return qname
+}
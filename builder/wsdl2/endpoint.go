@@ -0,0 +1,9 @@
+package wsdl2
+
+// Endpoint defines the address a binding is exposed at.
+type Endpoint struct {
+	Name    string `xml:"name,attr"`
+	Binding string `xml:"binding,attr"`
+	Address string `xml:"address,attr"`
+	Doc     string `xml:"documentation"`
+}
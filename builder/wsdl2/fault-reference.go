@@ -0,0 +1,7 @@
+package wsdl2
+
+// FaultReference associates an interface fault with a message of an operation.
+type FaultReference struct {
+	Ref          string `xml:"ref,attr"`
+	MessageLabel string `xml:"messageLabel,attr"`
+}
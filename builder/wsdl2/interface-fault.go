@@ -0,0 +1,8 @@
+package wsdl2
+
+// InterfaceFault declares a fault of an interface and the element carrying its details.
+type InterfaceFault struct {
+	Name    string `xml:"name,attr"`
+	Element string `xml:"element,attr"`
+	Doc     string `xml:"documentation"`
+}
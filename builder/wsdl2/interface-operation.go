@@ -0,0 +1,37 @@
+package wsdl2
+
+// InterfaceOperation represents an operation of an interface and its message exchange pattern.
+type InterfaceOperation struct {
+	Name      string              `xml:"name,attr"`
+	Pattern   string              `xml:"pattern,attr"`
+	Doc       string              `xml:"documentation"`
+	Inputs    []*MessageReference `xml:"http://www.w3.org/ns/wsdl input"`
+	Outputs   []*MessageReference `xml:"http://www.w3.org/ns/wsdl output"`
+	InFaults  []*FaultReference   `xml:"http://www.w3.org/ns/wsdl infault"`
+	OutFaults []*FaultReference   `xml:"http://www.w3.org/ns/wsdl outfault"`
+}
+
+// pattern returns the message exchange pattern of the operation, in-out being the default.
+func (o *InterfaceOperation) pattern() string {
+	if o.Pattern == "" {
+		return PatternInOut
+	}
+
+	return o.Pattern
+}
+
+func (o *InterfaceOperation) inputElement() string {
+	if len(o.Inputs) == 0 {
+		return ""
+	}
+
+	return o.Inputs[0].element()
+}
+
+func (o *InterfaceOperation) outputElement() string {
+	if len(o.Outputs) == 0 {
+		return ""
+	}
+
+	return o.Outputs[0].element()
+}
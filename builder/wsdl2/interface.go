@@ -0,0 +1,11 @@
+package wsdl2
+
+// Interface defines the operations of a service along with the faults they may raise,
+// it is the WSDL 2.0 counterpart of a port type.
+type Interface struct {
+	Name       string                `xml:"name,attr"`
+	Extends    string                `xml:"extends,attr"`
+	Doc        string                `xml:"documentation"`
+	Faults     []*InterfaceFault     `xml:"http://www.w3.org/ns/wsdl fault"`
+	Operations []*InterfaceOperation `xml:"http://www.w3.org/ns/wsdl operation"`
+}
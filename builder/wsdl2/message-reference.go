@@ -0,0 +1,23 @@
+package wsdl2
+
+import "log"
+
+// MessageReference declares the element of an input or output message of an operation.
+type MessageReference struct {
+	MessageLabel string `xml:"messageLabel,attr"`
+	Element      string `xml:"element,attr"`
+}
+
+// element returns the qualified name of the message element, or an empty string for the #none, #any and #other
+// message content models which don't declare one.
+func (m *MessageReference) element() string {
+	switch m.Element {
+	case "", "#none":
+		return ""
+	case "#any", "#other":
+		log.Printf("[WARN] %s message content model is not supported, ignoring message content...", m.Element)
+		return ""
+	}
+
+	return m.Element
+}
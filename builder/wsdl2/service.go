@@ -0,0 +1,9 @@
+package wsdl2
+
+// Service groups the endpoints exposing an interface.
+type Service struct {
+	Name      string      `xml:"name,attr"`
+	Interface string      `xml:"interface,attr"`
+	Doc       string      `xml:"documentation"`
+	Endpoints []*Endpoint `xml:"http://www.w3.org/ns/wsdl endpoint"`
+}
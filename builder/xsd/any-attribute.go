@@ -0,0 +1,10 @@
+package xsd
+
+import "encoding/xml"
+
+// AnyAttribute represents a Schema attribute wildcard, allowing attributes the schema does not declare.
+type AnyAttribute struct {
+	XMLName         xml.Name `xml:"anyAttribute"`
+	Namespace       string   `xml:"namespace,attr"`
+	ProcessContents string   `xml:"processContents,attr"`
+}
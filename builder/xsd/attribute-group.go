@@ -0,0 +1,11 @@
+package xsd
+
+// AttributeGroup element is used to define a group of attributes to be used in complex type definitions.
+type AttributeGroup struct {
+	Name            string            `xml:"name,attr"`
+	Ref             string            `xml:"ref,attr"`
+	Doc             string            `xml:"annotation>documentation"`
+	Attributes      []*Attribute      `xml:"attribute"`
+	AttributeGroups []*AttributeGroup `xml:"attributeGroup"`
+	AnyAttribute    *AnyAttribute     `xml:"anyAttribute"`
+}
@@ -9,7 +9,13 @@ type Attribute struct {
 	Ref        string      `xml:"ref,attr"`
 	Type       string      `xml:"type,attr"`
 	Use        string      `xml:"use,attr"`
+	Default    string      `xml:"default,attr"`
 	Fixed      string      `xml:"fixed,attr"`
+	Form       string      `xml:"form,attr"`
 	SimpleType *SimpleType `xml:"simpleType"`
 	Abstract   bool        `xml:"abstract,attr"`
+	ArrayType  string      `xml:"http://schemas.xmlsoap.org/wsdl/ arrayType,attr"`
+
+	// Namespace is the namespace a qualified attribute is written in, empty for unqualified ones.
+	Namespace string `xml:"-"`
 }
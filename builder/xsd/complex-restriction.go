@@ -0,0 +1,14 @@
+package xsd
+
+import "encoding/xml"
+
+// ComplexRestriction element restricts an existing complexType, the content kept from the base type is restated
+// and attributes are inherited unless prohibited. SOAP encoded arrays are declared this way.
+type ComplexRestriction struct {
+	XMLName         xml.Name          `xml:"restriction"`
+	Base            string            `xml:"base,attr"`
+	Attributes      []*Attribute      `xml:"attribute"`
+	AttributeGroups []*AttributeGroup `xml:"attributeGroup"`
+	AnyAttribute    *AnyAttribute     `xml:"anyAttribute"`
+	ContentModel
+}
@@ -0,0 +1,31 @@
+package xsd
+
+// ContentModel is the particle the content of a complex type is made of: a model group, or a reference to a named
+// group.
+type ContentModel struct {
+	Sequence *ModelGroup `xml:"sequence"`
+	Choice   *ModelGroup `xml:"choice"`
+	All      *ModelGroup `xml:"all"`
+	Group    *Group      `xml:"group"`
+}
+
+// ModelGroup returns the model group of the content, or nil when there is none.
+func (c *ContentModel) ModelGroup() *ModelGroup {
+	switch {
+	case c.Sequence != nil:
+		return c.Sequence
+	case c.Choice != nil:
+		return c.Choice
+	default:
+		return c.All
+	}
+}
+
+// Elements returns the elements of the content, in document order.
+func (c *ContentModel) Elements() []*Element {
+	if group := c.ModelGroup(); group != nil {
+		return group.Elements()
+	}
+
+	return nil
+}
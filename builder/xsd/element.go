@@ -4,15 +4,23 @@ import "encoding/xml"
 
 // Element represents a Schema element.
 type Element struct {
-	XMLName     xml.Name     `xml:"element"`
-	Name        string       `xml:"name,attr"`
-	Doc         string       `xml:"annotation>documentation"`
-	Nillable    bool         `xml:"nillable,attr"`
-	Type        string       `xml:"type,attr"`
-	Ref         string       `xml:"ref,attr"`
-	MinOccurs   string       `xml:"minOccurs,attr"`
-	MaxOccurs   string       `xml:"maxOccurs,attr"`
-	ComplexType *ComplexType `xml:"complexType"` // local
-	SimpleType  *SimpleType  `xml:"simpleType"`
-	Groups      []*Group     `xml:"group"`
+	XMLName           xml.Name     `xml:"element"`
+	Name              string       `xml:"name,attr"`
+	Doc               string       `xml:"annotation>documentation"`
+	Nillable          bool         `xml:"nillable,attr"`
+	Abstract          bool         `xml:"abstract,attr"`
+	Type              string       `xml:"type,attr"`
+	Ref               string       `xml:"ref,attr"`
+	SubstitutionGroup string       `xml:"substitutionGroup,attr"`
+	MinOccurs         string       `xml:"minOccurs,attr"`
+	MaxOccurs         string       `xml:"maxOccurs,attr"`
+	Default           string       `xml:"default,attr"`
+	Fixed             string       `xml:"fixed,attr"`
+	Form              string       `xml:"form,attr"`
+	ComplexType       *ComplexType `xml:"complexType"` // local
+	SimpleType        *SimpleType  `xml:"simpleType"`
+	Groups            []*Group     `xml:"group"`
+
+	// Namespace is the target namespace of the schema declaring a local element copied into another schema.
+	Namespace string `xml:"-"`
 }
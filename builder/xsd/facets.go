@@ -0,0 +1,180 @@
+package xsd
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Facets are the constraining facets of a simple type, each of them being checked when set. Patterns are XML
+// Schema regular expressions, the ones RE2 does not support, such as character class subtractions, being ignored
+// once the generator has warned about them.
+type Facets struct {
+	Pattern      string
+	MinInclusive string
+	MaxInclusive string
+	Length       string
+	MinLength    string
+	MaxLength    string
+	Enumeration  []string
+}
+
+// patterns caches the compiled patterns by expression, nil standing for the ones that do not compile.
+var patterns sync.Map
+
+// timed is implemented by the date and time types.
+type timed interface {
+	Time() time.Time
+}
+
+// check returns the messages of the facets a value breaks.
+func (f Facets) check(v reflect.Value) []string {
+	text, err := lexicalForm(v)
+	if err != nil {
+		return nil
+	}
+
+	var messages []string
+
+	if len(f.Enumeration) > 0 && !containsValue(f.Enumeration, text) {
+		messages = append(messages, fmt.Sprintf("%q is not one of the enumerated values", text))
+	}
+
+	if f.Pattern != "" {
+		if pattern := compilePattern(f.Pattern); pattern != nil && !pattern.MatchString(text) {
+			messages = append(messages, fmt.Sprintf("%q does not match the pattern %q", text, f.Pattern))
+		}
+	}
+
+	if length, ok := valueLength(v, text); ok {
+		if n, err := strconv.Atoi(f.Length); err == nil && length != n {
+			messages = append(messages, fmt.Sprintf("has length %d, %d expected", length, n))
+		}
+
+		if n, err := strconv.Atoi(f.MinLength); err == nil && length < n {
+			messages = append(messages, fmt.Sprintf("has length %d, at least %d expected", length, n))
+		}
+
+		if n, err := strconv.Atoi(f.MaxLength); err == nil && length > n {
+			messages = append(messages, fmt.Sprintf("has length %d, at most %d expected", length, n))
+		}
+	}
+
+	if f.MinInclusive != "" {
+		if c, ok := compareValue(v, f.MinInclusive); ok && c < 0 {
+			messages = append(messages, fmt.Sprintf("%s is less than %s", text, f.MinInclusive))
+		}
+	}
+
+	if f.MaxInclusive != "" {
+		if c, ok := compareValue(v, f.MaxInclusive); ok && c > 0 {
+			messages = append(messages, fmt.Sprintf("%s is greater than %s", text, f.MaxInclusive))
+		}
+	}
+
+	return messages
+}
+
+// CheckPattern returns the error met compiling a pattern facet, which is then not checked.
+func CheckPattern(expr string) error {
+	_, err := regexp.Compile(anchorPattern(expr))
+	return err
+}
+
+func compilePattern(expr string) *regexp.Regexp {
+	if pattern, ok := patterns.Load(expr); ok {
+		return pattern.(*regexp.Regexp)
+	}
+
+	pattern, err := regexp.Compile(anchorPattern(expr))
+	if err != nil {
+		pattern = nil
+	}
+
+	patterns.Store(expr, pattern)
+	return pattern
+}
+
+// anchorPattern returns the regular expression of a pattern, XML Schema patterns matching whole values.
+func anchorPattern(expr string) string {
+	return `^(?:` + expr + `)$`
+}
+
+// valueLength returns the length of a value as the length facets count it: items for lists, octets for binary
+// values and characters for strings. Other values have none.
+func valueLength(v reflect.Value, text string) (int, bool) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.Len(), true
+	case reflect.String:
+		return utf8.RuneCountInString(text), true
+	}
+
+	return 0, false
+}
+
+// compareValue compares a numeric, date or time value with the lexical form of a bound of the same type.
+func compareValue(v reflect.Value, bound string) (int, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		text, err := lexicalForm(v)
+		if err != nil {
+			return 0, false
+		}
+
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		b, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		return compare(value < b, value > b), true
+	}
+
+	value := reflect.New(v.Type())
+	value.Elem().Set(v)
+
+	t, ok := value.Interface().(timed)
+	if !ok {
+		return 0, false
+	}
+
+	b := reflect.New(v.Type())
+	if err := parseLexicalForm(b.Elem(), bound); err != nil {
+		return 0, false
+	}
+
+	limit := b.Interface().(timed).Time()
+	return compare(t.Time().Before(limit), t.Time().After(limit)), true
+}
+
+func compare(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+
+	return 0
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
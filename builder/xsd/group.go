@@ -1,10 +1,12 @@
 package xsd
 
-// Group element is used to define a group of elements to be used in complex type definitions.
+// Group element is used to define a group of elements to be used in complex type definitions, or to refer to one
+// along with the number of times it occurs.
 type Group struct {
-	Name     string    `xml:"name,attr"`
-	Ref      string    `xml:"ref,attr"`
-	Sequence []Element `xml:"sequence>element"`
-	Choice   []Element `xml:"choice>element"`
-	All      []Element `xml:"all>element"`
+	Name      string `xml:"name,attr"`
+	Ref       string `xml:"ref,attr"`
+	MinOccurs string `xml:"minOccurs,attr"`
+	MaxOccurs string `xml:"maxOccurs,attr"`
+	Doc       string `xml:"annotation>documentation"`
+	ContentModel
 }
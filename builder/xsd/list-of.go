@@ -0,0 +1,153 @@
+package xsd
+
+import (
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ListOf holds the value of an xsd:list simple type, written as its items separated by spaces whether in an
+// element or in an attribute. The lexical form of an item is the one of an attribute of its type.
+type ListOf[T any] []T
+
+// MarshalXML implements xml.Marshaler on ListOf.
+func (l ListOf[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	text, err := l.text()
+	if err != nil {
+		return err
+	}
+
+	return e.EncodeElement(text, start)
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr on ListOf, empty lists not being written.
+func (l ListOf[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if len(l) == 0 {
+		return xml.Attr{}, nil
+	}
+
+	text, err := l.text()
+	if err != nil {
+		return xml.Attr{}, err
+	}
+
+	return xml.Attr{Name: name, Value: text}, nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler on ListOf.
+func (l *ListOf[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+
+	return l.parse(text)
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr on ListOf.
+func (l *ListOf[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	return l.parse(attr.Value)
+}
+
+func (l ListOf[T]) text() (string, error) {
+	items := make([]string, 0, len(l))
+
+	for _, item := range l {
+		text, err := lexicalForm(reflect.ValueOf(&item).Elem())
+		if err != nil {
+			return "", err
+		}
+
+		items = append(items, text)
+	}
+
+	return strings.Join(items, " "), nil
+}
+
+// parse reads the items of a list, collapsing the whitespace around and between them.
+func (l *ListOf[T]) parse(text string) error {
+	fields := strings.Fields(text)
+	items := make(ListOf[T], len(fields))
+
+	for i, field := range fields {
+		if err := parseLexicalForm(reflect.ValueOf(&items[i]).Elem(), field); err != nil {
+			return err
+		}
+	}
+
+	*l = items
+	return nil
+}
+
+// lexicalForm returns the lexical form of a simple value, the one it has in an attribute.
+func lexicalForm(v reflect.Value) (string, error) {
+	switch m := v.Interface().(type) {
+	case xml.MarshalerAttr:
+		attr, err := m.MarshalXMLAttr(xml.Name{Local: "item"})
+		return attr.Value, err
+	case encoding.TextMarshaler:
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("xsd: unsupported simple type %s", v.Type())
+}
+
+// parseLexicalForm reads the lexical form of a simple value into v.
+func parseLexicalForm(v reflect.Value, text string) error {
+	switch m := v.Addr().Interface().(type) {
+	case xml.UnmarshalerAttr:
+		return m.UnmarshalXMLAttr(xml.Attr{Name: xml.Name{Local: "item"}, Value: text})
+	case encoding.TextUnmarshaler:
+		return m.UnmarshalText([]byte(text))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("xsd: unsupported simple type %s", v.Type())
+	}
+
+	return nil
+}
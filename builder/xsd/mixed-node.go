@@ -0,0 +1,275 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MixedNode is a node of the content of a mixed complex type: a text node holding Text, or a child element named
+// Name holding Value. Child elements the schema does not declare are held as *Node values.
+type MixedNode struct {
+	Name  xml.Name    `json:"name"`
+	Text  string      `json:"text,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MixedElement is a child element declared by a mixed complex type, Value pointing to a value of its Go type.
+type MixedElement struct {
+	Name  xml.Name
+	Value interface{}
+}
+
+// TextNode creates a text node of mixed content.
+func TextNode(text string) MixedNode {
+	return MixedNode{Text: text}
+}
+
+// ElementNode creates a child element node of mixed content.
+func ElementNode(name xml.Name, value interface{}) MixedNode {
+	return MixedNode{Name: name, Value: value}
+}
+
+// IsText tells whether the node is a text node.
+func (n MixedNode) IsText() bool {
+	return n.Name.Local == ""
+}
+
+// MarshalXML implements xml.Marshaler on MixedNode, writing the text or the element of the node whatever the name
+// of the field holding it.
+func (n MixedNode) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	if n.IsText() {
+		return e.EncodeToken(xml.CharData(n.Text))
+	}
+
+	start := xml.StartElement{Name: n.Name}
+	if n.Value == nil {
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+
+		return e.EncodeToken(start.End())
+	}
+
+	return e.EncodeElement(n.Value, start)
+}
+
+// MixedElements returns the values of the child elements named name in mixed content, in document order.
+func MixedElements[T any](content []MixedNode, name xml.Name) []T {
+	var values []T
+
+	for _, n := range content {
+		if v, ok := n.Value.(T); ok && matchName(n.Name, name) {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// UnmarshalMixed decodes the element start into v, a pointer to the struct of a mixed complex type, its text and
+// child elements being held in document order by its []MixedNode field, which may be promoted from an embedded
+// base type. Child elements are read into values of the type of the declaration they match, adjacent text being
+// kept as a single node.
+func UnmarshalMixed(d *xml.Decoder, start xml.StartElement, v interface{}, elements ...MixedElement) error {
+	value := reflect.ValueOf(v).Elem()
+	if err := unmarshalMixedAttrs(value, start); err != nil {
+		return err
+	}
+
+	content, ok := mixedContent(value)
+	if !ok {
+		return fmt.Errorf("xsd: %s has no mixed content field", value.Type())
+	}
+
+	*content = nil
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			if n := len(*content); n > 0 && (*content)[n-1].IsText() {
+				(*content)[n-1].Text += string(t)
+				continue
+			}
+
+			*content = append(*content, TextNode(string(t)))
+
+		case xml.StartElement:
+			value, err := unmarshalMixedElement(d, t, elements)
+			if err != nil {
+				return err
+			}
+
+			*content = append(*content, ElementNode(t.Name, value))
+
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// unmarshalMixedElement reads a child element of mixed content into a value of the type of the declaration it
+// matches, or into a node when there is none.
+func unmarshalMixedElement(d *xml.Decoder, start xml.StartElement, elements []MixedElement) (interface{}, error) {
+	for _, element := range elements {
+		if matchName(start.Name, element.Name) {
+			value := reflect.New(reflect.TypeOf(element.Value).Elem())
+			if err := d.DecodeElement(value.Interface(), &start); err != nil {
+				return nil, err
+			}
+
+			return value.Elem().Interface(), nil
+		}
+	}
+
+	node := &Node{}
+	return node, node.UnmarshalXML(d, start)
+}
+
+// mixedContent returns the field of a struct holding mixed content, looked for in the structs it embeds when it
+// has none of its own.
+func mixedContent(v reflect.Value) (*[]MixedNode, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath != "" {
+			continue
+		}
+
+		if content, ok := v.Field(i).Addr().Interface().(*[]MixedNode); ok {
+			return content, true
+		}
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		if embedded, ok := embeddedStruct(v.Type().Field(i), v.Field(i)); ok {
+			if content, ok := mixedContent(embedded); ok {
+				return content, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// embeddedStruct returns the struct embedded by a field, allocating it when the field is a nil pointer.
+func embeddedStruct(f reflect.StructField, field reflect.Value) (reflect.Value, bool) {
+	if !f.Anonymous {
+		return reflect.Value{}, false
+	}
+
+	if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		field = field.Elem()
+	}
+
+	return field, field.Kind() == reflect.Struct
+}
+
+// unmarshalMixedAttrs reads the attributes of the element start into the fields of the struct v and the ones it
+// embeds, as encoding/xml would.
+func unmarshalMixedAttrs(v reflect.Value, start xml.StartElement) error {
+	var anyAttrs reflect.Value
+	matched := make(map[int]bool)
+
+	if err := readMixedAttrs(v, start, matched, &anyAttrs); err != nil {
+		return err
+	}
+
+	if anyAttrs.IsValid() {
+		for i, attr := range start.Attr {
+			if !matched[i] {
+				anyAttrs.Set(reflect.Append(anyAttrs, reflect.ValueOf(attr)))
+			}
+		}
+	}
+
+	return nil
+}
+
+func readMixedAttrs(v reflect.Value, start xml.StartElement, matched map[int]bool, anyAttrs *reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		f, field := v.Type().Field(i), v.Field(i)
+
+		if embedded, ok := embeddedStruct(f, field); ok {
+			if err := readMixedAttrs(embedded, start, matched, anyAttrs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch {
+		case f.PkgPath != "":
+			continue
+
+		case f.Name == "XMLName" && f.Type == reflect.TypeOf(xml.Name{}):
+			field.Set(reflect.ValueOf(start.Name))
+			continue
+		}
+
+		tag := strings.Split(f.Tag.Get("xml"), ",")
+		if !containsFlag(tag[1:], "attr") {
+			continue
+		}
+
+		if containsFlag(tag[1:], "any") {
+			if !anyAttrs.IsValid() && field.Type() == reflect.TypeOf([]xml.Attr(nil)) {
+				*anyAttrs = field
+			}
+			continue
+		}
+
+		name := xml.Name{Local: f.Name}
+		if tag[0] != "" {
+			if j := strings.LastIndex(tag[0], " "); j >= 0 {
+				name = xml.Name{Space: tag[0][:j], Local: tag[0][j+1:]}
+			} else {
+				name = xml.Name{Local: tag[0]}
+			}
+		}
+
+		for j, attr := range start.Attr {
+			if matched[j] || !matchName(attr.Name, name) {
+				continue
+			}
+
+			matched[j] = true
+
+			target := field
+			if target.Kind() == reflect.Ptr {
+				target.Set(reflect.New(target.Type().Elem()))
+				target = target.Elem()
+			}
+
+			if err := parseLexicalForm(target, attr.Value); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// matchName tells whether a name read matches the name of a declaration, declarations without a namespace
+// matching any.
+func matchName(name, declared xml.Name) bool {
+	return name.Local == declared.Local && (declared.Space == "" || name.Space == declared.Space)
+}
+
+func containsFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
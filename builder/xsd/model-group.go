@@ -0,0 +1,119 @@
+package xsd
+
+import "encoding/xml"
+
+// ModelGroup element is a sequence, choice or all compositor. Its particles are kept in document order, which is
+// the order the elements are written in.
+type ModelGroup struct {
+	XMLName   xml.Name
+	MinOccurs string
+	MaxOccurs string
+	Particles []*Particle
+}
+
+// Particle is a term of a model group: an element, a wildcard, a reference to a named group or a nested model group.
+type Particle struct {
+	Element    *Element
+	Any        *Any
+	Group      *Group
+	ModelGroup *ModelGroup
+}
+
+// Kind returns the compositor of the model group: sequence, choice or all.
+func (g *ModelGroup) Kind() string {
+	return g.XMLName.Local
+}
+
+// UnmarshalXML implements interface xml.Unmarshaler for ModelGroup, keeping its particles in document order.
+func (g *ModelGroup) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	g.XMLName = start.Name
+
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "minOccurs":
+			g.MinOccurs = attr.Value
+		case "maxOccurs":
+			g.MaxOccurs = attr.Value
+		}
+	}
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			particle := new(Particle)
+
+			switch t.Name.Local {
+			case "element":
+				particle.Element = new(Element)
+				err = d.DecodeElement(particle.Element, &t)
+			case "any":
+				particle.Any = new(Any)
+				err = d.DecodeElement(particle.Any, &t)
+			case "group":
+				particle.Group = new(Group)
+				err = d.DecodeElement(particle.Group, &t)
+			case "sequence", "choice", "all":
+				particle.ModelGroup = new(ModelGroup)
+				err = d.DecodeElement(particle.ModelGroup, &t)
+			default:
+				particle = nil
+				err = d.Skip()
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if particle != nil {
+				g.Particles = append(g.Particles, particle)
+			}
+
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Elements returns the elements of the model group and of its nested model groups, in document order.
+func (g *ModelGroup) Elements() []*Element {
+	var elements []*Element
+
+	for _, particle := range g.Particles {
+		switch {
+		case particle.Element != nil:
+			elements = append(elements, particle.Element)
+		case particle.ModelGroup != nil:
+			elements = append(elements, particle.ModelGroup.Elements()...)
+		}
+	}
+
+	return elements
+}
+
+// Copy returns a copy of the model group in which the elements and the nested model groups are copies as well.
+func (g *ModelGroup) Copy() *ModelGroup {
+	c := *g
+	c.Particles = make([]*Particle, 0, len(g.Particles))
+
+	for _, particle := range g.Particles {
+		p := *particle
+
+		if particle.Element != nil {
+			element := *particle.Element
+			p.Element = &element
+		}
+
+		if particle.ModelGroup != nil {
+			p.ModelGroup = particle.ModelGroup.Copy()
+		}
+
+		c.Particles = append(c.Particles, &p)
+	}
+
+	return &c
+}
@@ -0,0 +1,132 @@
+package xsd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+)
+
+const xmlSchemaInstance = "http://www.w3.org/2001/XMLSchema-instance"
+
+// Nillable holds a nillable element, which is either absent, present with xsi:nil="true" when Nil is set, or
+// present with Value when Valid is set. Absent elements are not written.
+type Nillable[T any] struct {
+	Value T
+	Valid bool
+	Nil   bool
+}
+
+// NewNillable creates a nillable element holding v.
+func NewNillable[T any](v T) Nillable[T] {
+	return Nillable[T]{Value: v, Valid: true}
+}
+
+// NewNil creates a nillable element written with xsi:nil="true".
+func NewNil[T any]() Nillable[T] {
+	return Nillable[T]{Nil: true}
+}
+
+// Get returns the value of the element and whether it has one.
+func (n Nillable[T]) Get() (T, bool) {
+	return n.Value, n.Valid
+}
+
+// IsNil tells whether the element is present with xsi:nil="true".
+func (n Nillable[T]) IsNil() bool {
+	return n.Nil && !n.Valid
+}
+
+// IsAbsent tells whether the element is neither valued nor nil.
+func (n Nillable[T]) IsAbsent() bool {
+	return !n.Valid && !n.Nil
+}
+
+// Interface returns the value of the element, or nil when it has none.
+func (n Nillable[T]) Interface() interface{} {
+	if !n.Valid {
+		return nil
+	}
+
+	return n.Value
+}
+
+// set values the element with the lexical form of its value.
+func (n *Nillable[T]) set(text string) error {
+	var v T
+	if err := parseLexicalForm(reflect.ValueOf(&v).Elem(), text); err != nil {
+		return err
+	}
+
+	n.Value, n.Valid, n.Nil = v, true, false
+	return nil
+}
+
+// MarshalXML implements xml.Marshaler on Nillable, writing xsi:nil="true" for nil elements.
+func (n Nillable[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch {
+	case n.Valid:
+		return e.EncodeElement(n.Value, start)
+
+	case n.Nil:
+		start.Attr = append(start.Attr,
+			xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: xmlSchemaInstance},
+			xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"},
+		)
+
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+
+		return e.EncodeToken(start.End())
+	}
+
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler on Nillable, an element with xsi:nil="true" being read as nil whatever
+// its content.
+func (n *Nillable[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var zero T
+	n.Value, n.Valid, n.Nil = zero, false, false
+
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "nil" && (attr.Name.Space == xmlSchemaInstance || attr.Name.Space == "xsi") && (attr.Value == "true" || attr.Value == "1") {
+			n.Nil = true
+			return d.Skip()
+		}
+	}
+
+	if err := d.DecodeElement(&n.Value, &start); err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler on Nillable, nil and absent elements being null.
+func (n Nillable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler on Nillable, null being read as nil.
+func (n *Nillable[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+	n.Value, n.Valid, n.Nil = zero, false, false
+
+	if string(data) == "null" {
+		n.Nil = true
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
@@ -0,0 +1,174 @@
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// Node is an XML element held as is, such as the content of an xs:any wildcard, or a text node when it has no
+// name. Elements keep their attributes, the namespaces they declare and their children in document order, so
+// that they are written back as they were read.
+type Node struct {
+	XMLName xml.Name `json:"name"`
+
+	// Attrs are the attributes of the element, namespace declarations excluded.
+	Attrs []xml.Attr `json:"attrs,omitempty"`
+
+	// Namespaces are the prefixed namespaces the element declares, by prefix in Name.Local.
+	Namespaces []xml.Attr `json:"namespaces,omitempty"`
+
+	Children []*Node `json:"children,omitempty"`
+	Text     string  `json:"text,omitempty"`
+}
+
+// IsText tells whether the node is a text node.
+func (n *Node) IsText() bool {
+	return n.XMLName.Local == ""
+}
+
+// Attr returns the value of the attribute name, matched by local name when name has no namespace.
+func (n *Node) Attr(name xml.Name) (string, bool) {
+	for _, attr := range n.Attrs {
+		if matchName(attr.Name, name) {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// InnerText returns the text the element holds, the one of its descendants included.
+func (n *Node) InnerText() string {
+	if n.IsText() {
+		return n.Text
+	}
+
+	var text strings.Builder
+	for _, child := range n.Children {
+		text.WriteString(child.InnerText())
+	}
+
+	return text.String()
+}
+
+// Elements returns the child elements named name, all of them when name is empty.
+func (n *Node) Elements(name xml.Name) []*Node {
+	var elements []*Node
+
+	for _, child := range n.Children {
+		if !child.IsText() && (name.Local == "" || matchName(child.XMLName, name)) {
+			elements = append(elements, child)
+		}
+	}
+
+	return elements
+}
+
+// Decode reads the element into v as encoding/xml would read it from the document.
+func (n *Node) Decode(v interface{}) error {
+	var buf bytes.Buffer
+
+	e := xml.NewEncoder(&buf)
+	if err := n.encode(e, nil); err != nil {
+		return err
+	}
+
+	if err := e.Flush(); err != nil {
+		return err
+	}
+
+	return xml.Unmarshal(buf.Bytes(), v)
+}
+
+// MarshalXML implements xml.Marshaler on Node, writing the element whatever the name of the field holding it.
+func (n *Node) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return n.encode(e, nil)
+}
+
+// UnmarshalXML implements xml.Unmarshaler on Node, adjacent text being kept as a single node.
+func (n *Node) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*n = Node{XMLName: start.Name}
+
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			n.Namespaces = append(n.Namespaces, xml.Attr{Name: xml.Name{Local: attr.Name.Local}, Value: attr.Value})
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			// the default namespace is declared again from the name of the element when it is written
+		default:
+			n.Attrs = append(n.Attrs, attr)
+		}
+	}
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			if last := len(n.Children) - 1; last >= 0 && n.Children[last].IsText() {
+				n.Children[last].Text += string(t)
+				continue
+			}
+
+			n.Children = append(n.Children, &Node{Text: string(t)})
+
+		case xml.StartElement:
+			child := &Node{}
+			if err := child.UnmarshalXML(d, t); err != nil {
+				return err
+			}
+
+			n.Children = append(n.Children, child)
+
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// encode writes the node, prefixes holding the namespaces declared by its ancestors by URI. Attributes are written
+// with the prefix declared for their namespace, encoding/xml making one up for the others.
+func (n *Node) encode(e *xml.Encoder, prefixes map[string]string) error {
+	if n.IsText() {
+		return e.EncodeToken(xml.CharData(n.Text))
+	}
+
+	start := xml.StartElement{Name: n.XMLName}
+
+	if len(n.Namespaces) > 0 {
+		inherited := prefixes
+		prefixes = make(map[string]string, len(inherited)+len(n.Namespaces))
+		for uri, prefix := range inherited {
+			prefixes[uri] = prefix
+		}
+
+		for _, ns := range n.Namespaces {
+			prefixes[ns.Value] = ns.Name.Local
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + ns.Name.Local}, Value: ns.Value})
+		}
+	}
+
+	for _, attr := range n.Attrs {
+		if prefix, ok := prefixes[attr.Name.Space]; ok && attr.Name.Space != "" {
+			attr.Name = xml.Name{Local: prefix + ":" + attr.Name.Local}
+		}
+
+		start.Attr = append(start.Attr, attr)
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, child := range n.Children {
+		if err := child.encode(e, prefixes); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
@@ -12,3 +12,24 @@ type Restriction struct {
 	MinLength    RestrictionValue   `xml:"minLength"`
 	MaxLength    RestrictionValue   `xml:"maxLength"`
 }
+
+// Inherit copies the facets of a base restriction that are not narrowed by this one.
+func (r *Restriction) Inherit(base Restriction) {
+	if len(r.Enumeration) == 0 {
+		r.Enumeration = append(r.Enumeration, base.Enumeration...)
+	}
+
+	for _, facet := range []struct{ derived, base *RestrictionValue }{
+		{&r.Pattern, &base.Pattern},
+		{&r.MinInclusive, &base.MinInclusive},
+		{&r.MaxInclusive, &base.MaxInclusive},
+		{&r.WhiteSpace, &base.WhiteSpace},
+		{&r.Length, &base.Length},
+		{&r.MinLength, &base.MinLength},
+		{&r.MaxLength, &base.MaxLength},
+	} {
+		if facet.derived.Value == "" {
+			*facet.derived = *facet.base
+		}
+	}
+}
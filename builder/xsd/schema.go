@@ -6,19 +6,22 @@ const xmlschema11 = "http://www.w3.org/2001/XMLSchema"
 
 // Schema represents an entire Schema structure.
 type Schema struct {
-	XMLName            xml.Name          `xml:"schema"`
-	Xmlns              map[string]string `xml:"-"`
-	Tns                string            `xml:"xmlns tns,attr"`
-	Xs                 string            `xml:"xmlns xs,attr"`
-	Version            string            `xml:"version,attr"`
-	TargetNamespace    string            `xml:"targetNamespace,attr"`
-	ElementFormDefault string            `xml:"elementFormDefault,attr"`
-	Includes           []*Include        `xml:"include"`
-	Imports            []*Import         `xml:"import"`
-	Elements           []*Element        `xml:"element"`
-	Attributes         []*Attribute      `xml:"attribute"`
-	ComplexTypes       []*ComplexType    `xml:"complexType"`
-	SimpleType         []*SimpleType     `xml:"simpleType"`
+	XMLName              xml.Name          `xml:"schema"`
+	Xmlns                map[string]string `xml:"-"`
+	Tns                  string            `xml:"xmlns tns,attr"`
+	Xs                   string            `xml:"xmlns xs,attr"`
+	Version              string            `xml:"version,attr"`
+	TargetNamespace      string            `xml:"targetNamespace,attr"`
+	ElementFormDefault   string            `xml:"elementFormDefault,attr"`
+	AttributeFormDefault string            `xml:"attributeFormDefault,attr"`
+	Includes             []*Include        `xml:"include"`
+	Imports              []*Import         `xml:"import"`
+	Elements             []*Element        `xml:"element"`
+	Attributes           []*Attribute      `xml:"attribute"`
+	ComplexTypes         []*ComplexType    `xml:"complexType"`
+	SimpleType           []*SimpleType     `xml:"simpleType"`
+	Groups               []*Group          `xml:"group"`
+	AttributeGroups      []*AttributeGroup `xml:"attributeGroup"`
 }
 
 // UnmarshalXML implements interface xml.Unmarshaler for XSDSchema.
@@ -32,6 +35,11 @@ func (s *Schema) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			continue
 		}
 
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			s.Xmlns[""] = attr.Value
+			continue
+		}
+
 		switch attr.Name.Local {
 		case "version":
 			s.Version = attr.Value
@@ -39,6 +47,8 @@ func (s *Schema) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			s.TargetNamespace = attr.Value
 		case "elementFormDefault":
 			s.ElementFormDefault = attr.Value
+		case "attributeFormDefault":
+			s.AttributeFormDefault = attr.Value
 		}
 	}
 
@@ -108,6 +118,22 @@ Loop:
 
 				s.SimpleType = append(s.SimpleType, x)
 
+			case "group":
+				x := new(Group)
+				if err := d.DecodeElement(x, &t); err != nil {
+					return err
+				}
+
+				s.Groups = append(s.Groups, x)
+
+			case "attributeGroup":
+				x := new(AttributeGroup)
+				if err := d.DecodeElement(x, &t); err != nil {
+					return err
+				}
+
+				s.AttributeGroups = append(s.AttributeGroups, x)
+
 			default:
 				err := d.Skip()
 				if err != nil {
@@ -124,3 +150,131 @@ Loop:
 
 	return nil
 }
+
+// QualifiesElement tells whether a local element declared with the given form, the schema default applying when
+// it is empty, is namespace qualified.
+func (s *Schema) QualifiesElement(form string) bool {
+	if form == "" {
+		form = s.ElementFormDefault
+	}
+
+	return form == "qualified"
+}
+
+// QualifiesAttribute tells whether a local attribute declared with the given form, the schema default applying
+// when it is empty, is namespace qualified.
+func (s *Schema) QualifiesAttribute(form string) bool {
+	if form == "" {
+		form = s.AttributeFormDefault
+	}
+
+	return form == "qualified"
+}
+
+// Without removes the top level elements, attributes, types, groups and attribute groups which are also declared by
+// another schema.
+func (s *Schema) Without(other *Schema) {
+	elements := s.Elements[:0]
+	for _, el := range s.Elements {
+		if !other.declaresElement(el.Name) {
+			elements = append(elements, el)
+		}
+	}
+	s.Elements = elements
+
+	attributes := s.Attributes[:0]
+	for _, attr := range s.Attributes {
+		if !other.declaresAttribute(attr.Name) {
+			attributes = append(attributes, attr)
+		}
+	}
+	s.Attributes = attributes
+
+	complexTypes := s.ComplexTypes[:0]
+	for _, ct := range s.ComplexTypes {
+		if !other.declaresType(ct.Name) {
+			complexTypes = append(complexTypes, ct)
+		}
+	}
+	s.ComplexTypes = complexTypes
+
+	simpleTypes := s.SimpleType[:0]
+	for _, st := range s.SimpleType {
+		if !other.declaresType(st.Name) {
+			simpleTypes = append(simpleTypes, st)
+		}
+	}
+	s.SimpleType = simpleTypes
+
+	groups := s.Groups[:0]
+	for _, group := range s.Groups {
+		if !other.declaresGroup(group.Name) {
+			groups = append(groups, group)
+		}
+	}
+	s.Groups = groups
+
+	attributeGroups := s.AttributeGroups[:0]
+	for _, group := range s.AttributeGroups {
+		if !other.declaresAttributeGroup(group.Name) {
+			attributeGroups = append(attributeGroups, group)
+		}
+	}
+	s.AttributeGroups = attributeGroups
+}
+
+func (s *Schema) declaresElement(name string) bool {
+	for _, el := range s.Elements {
+		if el.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Schema) declaresAttribute(name string) bool {
+	for _, attr := range s.Attributes {
+		if attr.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Schema) declaresType(name string) bool {
+	for _, ct := range s.ComplexTypes {
+		if ct.Name == name {
+			return true
+		}
+	}
+
+	for _, st := range s.SimpleType {
+		if st.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Schema) declaresGroup(name string) bool {
+	for _, group := range s.Groups {
+		if group.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Schema) declaresAttributeGroup(name string) bool {
+	for _, group := range s.AttributeGroups {
+		if group.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
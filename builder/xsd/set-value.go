@@ -0,0 +1,54 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"reflect"
+)
+
+// settable is implemented by the wrappers of values, such as nillable elements, set from a lexical form.
+type settable interface {
+	set(text string) error
+}
+
+// SetValue sets the field pointed to by field to the value of a lexical form, such as a default or fixed value,
+// allocating the pointers and setting the nillable elements on the way. The field is left unset when the lexical
+// form is not valid for its type, which the generator warns about.
+func SetValue(field interface{}, text string) {
+	v := reflect.ValueOf(field).Elem()
+
+	value := reflect.New(v.Type())
+	if ParseValue(value.Interface(), text) == nil {
+		v.Set(value.Elem())
+	}
+}
+
+// ParseValue reads a lexical form into the value pointed to by v, allocating the pointers and setting the nillable
+// elements on the way.
+func ParseValue(v interface{}, text string) error {
+	target := reflect.ValueOf(v).Elem()
+	for target.Kind() == reflect.Ptr {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+
+	if n, ok := target.Addr().Interface().(settable); ok {
+		return n.set(text)
+	}
+
+	return parseLexicalForm(target, text)
+}
+
+// MarshalFixedAttr writes an attribute having a fixed value, which is written when value is left empty.
+func MarshalFixedAttr(name xml.Name, value interface{}, fixed string) (xml.Attr, error) {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.IsZero() {
+		return xml.Attr{Name: name, Value: fixed}, nil
+	}
+
+	text, err := lexicalForm(v)
+	if err != nil {
+		return xml.Attr{}, err
+	}
+
+	return xml.Attr{Name: name, Value: text}, nil
+}
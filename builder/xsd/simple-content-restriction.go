@@ -0,0 +1,15 @@
+package xsd
+
+import "encoding/xml"
+
+// SimpleContentRestriction element restricts the value and the attributes of a complexType with simple content.
+// ValueType is not part of the schema, it holds the simple type of the value once the base type is resolved.
+type SimpleContentRestriction struct {
+	XMLName xml.Name `xml:"restriction"`
+	Restriction
+	SimpleType      *SimpleType       `xml:"simpleType"`
+	Attributes      []*Attribute      `xml:"attribute"`
+	AttributeGroups []*AttributeGroup `xml:"attributeGroup"`
+	AnyAttribute    *AnyAttribute     `xml:"anyAttribute"`
+	ValueType       string            `xml:"-"`
+}
@@ -0,0 +1,64 @@
+package xsd
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UnionMember is a member type of an xsd:union, Value pointing to a value of its Go type. Values of a member
+// declaring an enumeration must be one of the enumerated values.
+type UnionMember struct {
+	Value       interface{}
+	Enumeration []string
+}
+
+// ParseUnion reads the lexical form of a union value, trying the member types in the order they are declared. It
+// returns the index of the first member the value is valid for along with the value read.
+func ParseUnion(text string, members ...UnionMember) (int, interface{}, error) {
+	text = strings.TrimSpace(text)
+
+	for i, member := range members {
+		v := reflect.ValueOf(member.Value).Elem()
+		v.Set(reflect.Zero(v.Type()))
+
+		if err := parseLexicalForm(v, text); err != nil {
+			continue
+		}
+
+		if len(member.Enumeration) > 0 && !member.enumerates(text, v) {
+			continue
+		}
+
+		return i, v.Interface(), nil
+	}
+
+	return 0, nil, fmt.Errorf("xsd: %q is valid for no member type of the union", text)
+}
+
+// FormatUnion returns the lexical form of a union value, the canonical one of the member type it belongs to.
+func FormatUnion(value interface{}) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	text, err := lexicalForm(reflect.ValueOf(value))
+	return []byte(text), err
+}
+
+// enumerates tells whether a value read from text is one of the enumerated values, as written or in its
+// canonical form.
+func (m UnionMember) enumerates(text string, v reflect.Value) bool {
+	canonical, err := lexicalForm(v)
+	if err != nil {
+		canonical = text
+	}
+
+	for _, value := range m.Enumeration {
+		if value == text || value == canonical {
+			return true
+		}
+	}
+
+	return false
+}
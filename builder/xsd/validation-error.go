@@ -0,0 +1,30 @@
+package xsd
+
+import "strings"
+
+// ValidationError is a constraint of its schema a value breaks, Path locating the value within the one validated.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors are all the constraints a value breaks, as returned by the Validate methods of the generated
+// types.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,187 @@
+package xsd
+
+import (
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Validator is implemented by the generated types, which check their values against the constraints of their
+// schema.
+type Validator interface {
+	Validate() error
+}
+
+// nillable is implemented by Nillable, whatever the type of its value.
+type nillable interface {
+	IsAbsent() bool
+	Interface() interface{}
+}
+
+// Validation collects the constraints a value breaks, for the Validate methods of the generated types.
+type Validation struct {
+	errors ValidationErrors
+}
+
+// Required checks that a required element or attribute is set.
+func (v *Validation) Required(path string, value interface{}) {
+	if n, ok := value.(nillable); ok {
+		if n.IsAbsent() {
+			v.add(path, "is required")
+		}
+		return
+	}
+
+	if rv := reflect.ValueOf(value); !rv.IsValid() || rv.IsZero() {
+		v.add(path, "is required")
+	}
+}
+
+// Occurs checks that an element occurs between min and max times, max being negative when it is unbounded.
+func (v *Validation) Occurs(path string, n, min, max int) {
+	switch {
+	case n < min:
+		v.add(path, fmt.Sprintf("occurs %d times, at least %d expected", n, min))
+	case max >= 0 && n > max:
+		v.add(path, fmt.Sprintf("occurs %d times, at most %d expected", n, max))
+	}
+}
+
+// Facets checks a simple value against facets. Each value of a repeated element is checked on its own, while the
+// facets of lists apply to the whole list.
+func (v *Validation) Facets(path string, value interface{}, facets Facets) {
+	v.facets(path, reflect.ValueOf(value), facets)
+}
+
+func (v *Validation) facets(path string, value reflect.Value, facets Facets) {
+	value, ok := deref(value)
+	if !ok {
+		return
+	}
+
+	if value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8 && !isSimple(value) {
+		for i := 0; i < value.Len(); i++ {
+			v.facets(fmt.Sprintf("%s[%d]", path, i), value.Index(i), facets)
+		}
+		return
+	}
+
+	for _, message := range facets.check(value) {
+		v.add(path, message)
+	}
+}
+
+// Value validates the values a value holds: the ones implementing Validator, the items of slices and the fields of
+// anonymous structs.
+func (v *Validation) Value(path string, value interface{}) {
+	v.value(path, reflect.ValueOf(value))
+}
+
+func (v *Validation) value(path string, value reflect.Value) {
+	value, ok := deref(value)
+	if !ok {
+		return
+	}
+
+	if value.CanInterface() {
+		if validator, ok := value.Interface().(Validator); ok {
+			v.merge(path, validator.Validate())
+			return
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			v.value(fmt.Sprintf("%s[%d]", path, i), value.Index(i))
+		}
+
+	case reflect.Struct:
+		if value.Type().Name() != "" {
+			return
+		}
+
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); field.PkgPath == "" {
+				v.value(joinPath(path, field.Name), value.Field(i))
+			}
+		}
+	}
+}
+
+// Err returns the constraints broken as ValidationErrors, or nil when there are none.
+func (v *Validation) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return v.errors
+}
+
+func (v *Validation) add(path, message string) {
+	v.errors = append(v.errors, &ValidationError{Path: path, Message: message})
+}
+
+// merge adds the errors of a value validated at path.
+func (v *Validation) merge(path string, err error) {
+	switch err := err.(type) {
+	case nil:
+	case ValidationErrors:
+		for _, e := range err {
+			v.add(joinPath(path, e.Path), e.Message)
+		}
+	default:
+		v.add(path, err.Error())
+	}
+}
+
+// deref returns the value held by pointers, interfaces and nillable elements, telling whether there is one.
+func deref(value reflect.Value) (reflect.Value, bool) {
+	for value.IsValid() {
+		if value.CanInterface() {
+			if n, ok := value.Interface().(nillable); ok {
+				value = reflect.ValueOf(n.Interface())
+				continue
+			}
+		}
+
+		if value.Kind() != reflect.Ptr && value.Kind() != reflect.Interface {
+			return value, true
+		}
+
+		if value.IsNil() {
+			return value, false
+		}
+
+		value = value.Elem()
+	}
+
+	return value, false
+}
+
+// isSimple tells whether a value is written as text, like the values of list types.
+func isSimple(value reflect.Value) bool {
+	if !value.CanInterface() {
+		return false
+	}
+
+	switch value.Interface().(type) {
+	case xml.MarshalerAttr, encoding.TextMarshaler:
+		return true
+	}
+
+	return false
+}
+
+func joinPath(path, child string) string {
+	switch {
+	case path == "":
+		return child
+	case child == "" || strings.HasPrefix(child, "["):
+		return path + child
+	}
+
+	return path + "." + child
+}
@@ -2,35 +2,25 @@ package builder
 
 import (
 	"encoding/xml"
-	"strings"
+	"log"
+	"strconv"
 
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
 	"github.com/go-aegian/gowsdlsoap/builder/xsd"
 )
 
-type parseMode int32
-
-const (
-	refResolution parseMode = iota
-	findNameByType
-)
-
+// xsdParser resolves the references of a schema, expanding groups and completing derivations, before the schemas
+// are turned into Go code. References are resolved through the symbol table of all the schemas.
 type xsdParser struct {
-	c                 *xsd.Schema
-	all               []*xsd.Schema
-	mode              parseMode
-	typeName          string
-	foundElementName  string
-	namespace         string
-	typeUsageConflict bool
+	c       *xsd.Schema
+	symbols *symbols
 }
 
-func NewXsdParser(c *xsd.Schema, all []*xsd.Schema) *xsdParser {
-	return &xsdParser{c: c, all: all, mode: refResolution}
+func NewXsdParser(c *xsd.Schema, symbols *symbols) *xsdParser {
+	return &xsdParser{c: c, symbols: symbols}
 }
 
 func (t *xsdParser) parse() {
-	t.mode = refResolution
-
 	for _, ct := range t.c.ComplexTypes {
 		t.parseComplexType(ct)
 	}
@@ -51,8 +41,6 @@ func (t *xsdParser) parseElements(elements []*xsd.Element) {
 }
 
 func (t *xsdParser) parseElement(element *xsd.Element) {
-	t.findElementName(element)
-
 	if element.ComplexType != nil {
 		t.parseComplexType(element.ComplexType)
 	}
@@ -66,16 +54,386 @@ func (t *xsdParser) parseSimpleType(st *xsd.SimpleType) {
 }
 
 func (t *xsdParser) parseComplexType(ct *xsd.ComplexType) {
-	t.parseElements(ct.Sequence)
-	t.parseElements(ct.Choice)
-	t.parseElements(ct.SequenceChoice)
-	t.parseElements(ct.All)
+	t.expandGroups(ct)
+	t.resolveRestrictions(ct, nil)
+
+	t.parseElements(ct.Elements())
 	t.parseAttributes(ct.Attributes)
 	t.parseAttributes(ct.ComplexContent.Extension.Attributes)
-	t.parseElements(ct.ComplexContent.Extension.Sequence)
-	t.parseElements(ct.ComplexContent.Extension.Choice)
-	t.parseElements(ct.ComplexContent.Extension.SequenceChoice)
+	t.parseElements(ct.ComplexContent.Extension.Elements())
 	t.parseAttributes(ct.SimpleContent.Extension.Attributes)
+	t.parseElements(ct.ComplexContent.Restriction.Elements())
+}
+
+// expandGroups replaces the group and attributeGroup references of a complex type, and of its derivations, by
+// the model groups and attributes they define. References are dropped once expanded.
+func (t *xsdParser) expandGroups(ct *xsd.ComplexType) {
+	t.expandContentModel(&ct.ContentModel)
+	ct.Attributes = append(ct.Attributes, t.attributeGroupsContent(ct.AttributeGroups, nil)...)
+	ct.AnyAttribute = t.attributeWildcard(ct.AnyAttribute, ct.AttributeGroups)
+	ct.AttributeGroups = nil
+
+	for _, extension := range []*xsd.Extension{&ct.ComplexContent.Extension, &ct.SimpleContent.Extension} {
+		t.expandContentModel(&extension.ContentModel)
+		extension.Attributes = append(extension.Attributes, t.attributeGroupsContent(extension.AttributeGroups, nil)...)
+		extension.AnyAttribute = t.attributeWildcard(extension.AnyAttribute, extension.AttributeGroups)
+		extension.AttributeGroups = nil
+	}
+
+	restriction := &ct.ComplexContent.Restriction
+	t.expandContentModel(&restriction.ContentModel)
+	restriction.Attributes = append(restriction.Attributes, t.attributeGroupsContent(restriction.AttributeGroups, nil)...)
+	restriction.AnyAttribute = t.attributeWildcard(restriction.AnyAttribute, restriction.AttributeGroups)
+	restriction.AttributeGroups = nil
+
+	simpleRestriction := &ct.SimpleContent.Restriction
+	simpleRestriction.Attributes = append(simpleRestriction.Attributes, t.attributeGroupsContent(simpleRestriction.AttributeGroups, nil)...)
+	simpleRestriction.AnyAttribute = t.attributeWildcard(simpleRestriction.AnyAttribute, simpleRestriction.AttributeGroups)
+	simpleRestriction.AttributeGroups = nil
+}
+
+// attributeWildcard returns the attribute wildcard of a complex type or derivation, which is its own or else the
+// one of the attribute groups it references.
+func (t *xsdParser) attributeWildcard(wildcard *xsd.AnyAttribute, groups []*xsd.AttributeGroup) *xsd.AnyAttribute {
+	if wildcard != nil {
+		return wildcard
+	}
+
+	return t.attributeGroupsWildcard(groups, make(map[*xsd.AttributeGroup]bool))
+}
+
+func (t *xsdParser) attributeGroupsWildcard(groups []*xsd.AttributeGroup, expanding map[*xsd.AttributeGroup]bool) *xsd.AnyAttribute {
+	for _, ref := range groups {
+		schema, group := t.getGlobalAttributeGroup(ref.Ref)
+		if group == nil || expanding[group] {
+			continue
+		}
+
+		if group.AnyAttribute != nil {
+			return group.AnyAttribute
+		}
+
+		expanding[group] = true
+		if wildcard := NewXsdParser(schema, t.symbols).attributeGroupsWildcard(group.AttributeGroups, expanding); wildcard != nil {
+			return wildcard
+		}
+	}
+
+	return nil
+}
+
+// expandContentModel expands the group references of a content model. A content made of a group reference is
+// turned into a sequence holding the group.
+func (t *xsdParser) expandContentModel(content *xsd.ContentModel) {
+	if content.Group != nil {
+		content.Sequence = &xsd.ModelGroup{
+			XMLName:   xml.Name{Local: "sequence"},
+			Particles: []*xsd.Particle{{Group: content.Group}},
+		}
+		content.Group = nil
+	}
+
+	if group := content.ModelGroup(); group != nil {
+		t.expandModelGroup(group, nil)
+	}
+}
+
+// expandModelGroup replaces the group references of a model group, and of its nested model groups, by copies of
+// the model groups they refer to. References that cannot be expanded are dropped.
+func (t *xsdParser) expandModelGroup(group *xsd.ModelGroup, expanding map[*xsd.Group]bool) {
+	particles := group.Particles[:0]
+
+	for _, particle := range group.Particles {
+		switch {
+		case particle.Group != nil:
+			if particle.ModelGroup = t.groupContent(particle.Group, expanding); particle.ModelGroup == nil {
+				continue
+			}
+			particle.Group = nil
+
+		case particle.ModelGroup != nil:
+			t.expandModelGroup(particle.ModelGroup, expanding)
+		}
+
+		particles = append(particles, particle)
+	}
+
+	group.Particles = particles
+}
+
+// resolveRestrictions completes the restriction derivations of a complex type with what they inherit from their
+// base type: the attributes that are neither restated nor prohibited, the types of restated elements and attributes
+// that omit them and, for simple content, the value type along with its enumerations and facets.
+// Completing a restriction twice gives the same result, so base types are completed on demand whatever the order.
+func (t *xsdParser) resolveRestrictions(ct *xsd.ComplexType, resolving map[*xsd.ComplexType]bool) {
+	if resolving == nil {
+		resolving = make(map[*xsd.ComplexType]bool)
+	}
+
+	if resolving[ct] {
+		log.Printf("[WARN] Complex type %s derives from itself, ignoring derivation...", ct.Name)
+		return
+	}
+
+	resolving[ct] = true
+	defer delete(resolving, ct)
+
+	if restriction := &ct.ComplexContent.Restriction; restriction.Base != "" && schemaScope(t.c).resolve(restriction.Base).Space != soap.XmlNsSoapEnc {
+		t.parseAttributes(restriction.Attributes)
+
+		if parser, base := t.getBaseComplexType(restriction.Base, resolving); base != nil {
+			restriction.Attributes = restrictAttributes(t.importAttributes(parser.complexTypeAttributes(base, resolving), parser.c), restriction.Attributes)
+
+			restrictElements(t.importElements(parser.complexTypeElements(base, resolving), parser.c), restriction.Elements())
+		}
+	}
+
+	if restriction := &ct.SimpleContent.Restriction; restriction.Base != "" {
+		t.parseAttributes(restriction.Attributes)
+		restriction.ValueType = restriction.Base
+
+		parser, base := t.getBaseComplexType(restriction.Base, resolving)
+		if base != nil {
+			restriction.Attributes = restrictAttributes(t.importAttributes(parser.complexTypeAttributes(base, resolving), parser.c), restriction.Attributes)
+
+			if base.SimpleContent.Restriction.Base != "" {
+				restriction.ValueType = t.requalify(base.SimpleContent.Restriction.ValueType, parser.c)
+				restriction.Restriction.Inherit(base.SimpleContent.Restriction.Restriction)
+			} else {
+				restriction.ValueType = t.requalify(parser.simpleContentValueType(base.SimpleContent.Extension.Base, resolving), parser.c)
+			}
+		}
+
+		// an inline simple type narrows the value further
+		if restriction.SimpleType != nil {
+			restriction.Restriction.Inherit(restriction.SimpleType.Restriction)
+			if restriction.SimpleType.Restriction.Base != "" {
+				restriction.ValueType = restriction.SimpleType.Restriction.Base
+			}
+		}
+	}
+}
+
+// getBaseComplexType returns the base complex type of a derivation, with its groups expanded and its own
+// restrictions completed, along with a parser for the schema defining it.
+func (t *xsdParser) getBaseComplexType(name string, resolving map[*xsd.ComplexType]bool) (*xsdParser, *xsd.ComplexType) {
+	schema, base := t.getGlobalComplexType(name)
+	if base == nil {
+		return nil, nil
+	}
+
+	parser := NewXsdParser(schema, t.symbols)
+	parser.expandGroups(base)
+	parser.resolveRestrictions(base, resolving)
+
+	return parser, base
+}
+
+// complexTypeAttributes returns all the attributes of a complex type, including the ones inherited from its
+// ancestors.
+func (t *xsdParser) complexTypeAttributes(ct *xsd.ComplexType, resolving map[*xsd.ComplexType]bool) []*xsd.Attribute {
+	var attributes []*xsd.Attribute
+
+	switch {
+	case ct.ComplexContent.Restriction.Base != "":
+		return ct.ComplexContent.Restriction.Attributes
+
+	case ct.SimpleContent.Restriction.Base != "":
+		return ct.SimpleContent.Restriction.Attributes
+
+	case ct.ComplexContent.Extension.Base != "" || ct.SimpleContent.Extension.Base != "":
+		extension := &ct.ComplexContent.Extension
+		if extension.Base == "" {
+			extension = &ct.SimpleContent.Extension
+		}
+
+		if parser, base := t.getBaseComplexType(extension.Base, resolving); base != nil {
+			attributes = append(attributes, t.importAttributes(parser.complexTypeAttributes(base, resolving), parser.c)...)
+		}
+
+		t.parseAttributes(extension.Attributes)
+		return append(attributes, extension.Attributes...)
+	}
+
+	t.parseAttributes(ct.Attributes)
+	return ct.Attributes
+}
+
+// complexTypeElements returns all the elements of a complex type, including the ones inherited from its ancestors.
+func (t *xsdParser) complexTypeElements(ct *xsd.ComplexType, resolving map[*xsd.ComplexType]bool) []*xsd.Element {
+	var elements []*xsd.Element
+
+	switch {
+	case ct.ComplexContent.Restriction.Base != "":
+		return ct.ComplexContent.Restriction.Elements()
+
+	case ct.ComplexContent.Extension.Base != "":
+		extension := &ct.ComplexContent.Extension
+		if parser, base := t.getBaseComplexType(extension.Base, resolving); base != nil {
+			elements = append(elements, t.importElements(parser.complexTypeElements(base, resolving), parser.c)...)
+		}
+
+		return append(elements, extension.Elements()...)
+	}
+
+	return ct.Elements()
+}
+
+// simpleContentValueType returns the simple type of the value of a type used as the base of a simple content.
+func (t *xsdParser) simpleContentValueType(name string, resolving map[*xsd.ComplexType]bool) string {
+	parser, base := t.getBaseComplexType(name, resolving)
+	if base == nil {
+		return name
+	}
+
+	if base.SimpleContent.Restriction.Base != "" {
+		return t.requalify(base.SimpleContent.Restriction.ValueType, parser.c)
+	}
+
+	if base.SimpleContent.Extension.Base != "" {
+		return t.requalify(parser.simpleContentValueType(base.SimpleContent.Extension.Base, resolving), parser.c)
+	}
+
+	log.Printf("[WARN] %s has no simple content, using it as a string value...", name)
+	return "string"
+}
+
+// restrictAttributes merges the attributes of a base type with the ones restated by a restriction. Restated
+// attributes replace the inherited ones and take their type when omitting it. Prohibited attributes are kept,
+// flagged with their use, so the merge can be repeated.
+func restrictAttributes(inherited, restated []*xsd.Attribute) []*xsd.Attribute {
+	attributes := make([]*xsd.Attribute, 0, len(inherited)+len(restated))
+	merged := make(map[*xsd.Attribute]bool)
+
+	for _, attr := range inherited {
+		replaced := false
+
+		for _, r := range restated {
+			if r.Name == attr.Name {
+				if r.Type == "" && r.SimpleType == nil {
+					r.Type = attr.Type
+				}
+				attributes = append(attributes, r)
+				merged[r] = true
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			a := *attr
+			attributes = append(attributes, &a)
+		}
+	}
+
+	for _, r := range restated {
+		if !merged[r] {
+			attributes = append(attributes, r)
+		}
+	}
+
+	return attributes
+}
+
+// restrictElements gives the restated elements that omit their type the type they have in the base type.
+func restrictElements(inherited, restated []*xsd.Element) {
+	for _, r := range restated {
+		if r.Type != "" || r.Ref != "" || r.SimpleType != nil || r.ComplexType != nil {
+			continue
+		}
+
+		for _, el := range inherited {
+			if el.Name == r.Name {
+				r.Type = el.Type
+				break
+			}
+		}
+	}
+}
+
+// groupContent returns a copy of the model group of the named group a reference refers to, occurring as many
+// times as the reference tells, with its own references expanded. expanding holds the groups being expanded, a
+// group referring to one of them is ignored rather than looping.
+func (t *xsdParser) groupContent(ref *xsd.Group, expanding map[*xsd.Group]bool) *xsd.ModelGroup {
+	schema, group := t.getGlobalGroup(ref.Ref)
+	if group == nil {
+		log.Printf("[WARN] Group %s not found, ignoring group...", ref.Ref)
+		return nil
+	}
+
+	if expanding == nil {
+		expanding = make(map[*xsd.Group]bool)
+	}
+
+	if expanding[group] || group.ModelGroup() == nil {
+		return nil
+	}
+
+	expanding[group] = true
+	defer delete(expanding, group)
+
+	content := group.ModelGroup().Copy()
+	content.MinOccurs, content.MaxOccurs = ref.MinOccurs, ref.MaxOccurs
+
+	// references within the group are resolved against the schema defining it
+	NewXsdParser(schema, t.symbols).expandModelGroup(content, expanding)
+	t.requalifyElements(content.Elements(), schema)
+
+	return content
+}
+
+// attributeGroupsContent returns copies of the attributes of the referenced attribute groups, with their own
+// references resolved.
+func (t *xsdParser) attributeGroupsContent(groups []*xsd.AttributeGroup, expanding map[*xsd.AttributeGroup]bool) []*xsd.Attribute {
+	var attributes []*xsd.Attribute
+
+	if expanding == nil {
+		expanding = make(map[*xsd.AttributeGroup]bool)
+	}
+
+	for _, ref := range groups {
+		schema, group := t.getGlobalAttributeGroup(ref.Ref)
+		if group == nil {
+			log.Printf("[WARN] Attribute group %s not found, ignoring attribute group...", ref.Ref)
+			continue
+		}
+
+		if expanding[group] {
+			continue
+		}
+
+		expanding[group] = true
+
+		parser := NewXsdParser(schema, t.symbols)
+
+		for _, attr := range group.Attributes {
+			a := *attr
+			parser.parseAttribute(&a)
+			a.Ref = ""
+			t.requalifyAttribute(&a, schema)
+			attributes = append(attributes, &a)
+		}
+
+		for _, attr := range parser.attributeGroupsContent(group.AttributeGroups, expanding) {
+			t.requalifyAttribute(attr, schema)
+			attributes = append(attributes, attr)
+		}
+
+		delete(expanding, group)
+	}
+
+	return attributes
+}
+
+func copyElements(elements []*xsd.Element) []*xsd.Element {
+	copies := make([]*xsd.Element, 0, len(elements))
+
+	for _, el := range elements {
+		e := *el
+		copies = append(copies, &e)
+	}
+
+	return copies
 }
 
 func (t *xsdParser) parseAttributes(attrs []*xsd.Attribute) {
@@ -84,18 +442,25 @@ func (t *xsdParser) parseAttributes(attrs []*xsd.Attribute) {
 	}
 }
 
+// parseAttribute completes an attribute with what its reference declares and with the namespace it is written in,
+// global attributes being always qualified and local ones depending on their form.
 func (t *xsdParser) parseAttribute(attr *xsd.Attribute) {
-	if t.mode != refResolution {
-		return
-	}
-
 	if attr.Ref != "" {
-		refAttr := t.getGlobalAttribute(attr.Ref)
+		ref := schemaScope(t.c).resolve(attr.Ref)
+		attr.Namespace = ref.Space
+		if attr.Name == "" {
+			attr.Name = ref.Local
+		}
+
+		schema, refAttr := t.getGlobalAttribute(attr.Ref)
 		if refAttr != nil && refAttr.Ref == "" {
-			t.parseAttribute(refAttr)
+			NewXsdParser(schema, t.symbols).parseAttribute(refAttr)
 			attr.Name = refAttr.Name
-			attr.Type = refAttr.Type
+			attr.Type = t.requalify(refAttr.Type, schema)
 			attr.Abstract = refAttr.Abstract
+			if attr.Default == "" {
+				attr.Default = refAttr.Default
+			}
 			if attr.Fixed == "" {
 				attr.Fixed = refAttr.Fixed
 			}
@@ -103,103 +468,135 @@ func (t *xsdParser) parseAttribute(attr *xsd.Attribute) {
 		return
 	}
 
+	if t.c.QualifiesAttribute(attr.Form) {
+		attr.Namespace = t.c.TargetNamespace
+	}
+
 	if attr.Type == "" && attr.SimpleType != nil {
 		t.parseSimpleType(attr.SimpleType)
 		attr.Type = attr.SimpleType.Restriction.Base
 	}
 }
 
-func (t *xsdParser) findElementName(element *xsd.Element) {
-	if t.mode != findNameByType || t.typeUsageConflict {
-		return
+func (t *xsdParser) getGlobalAttribute(name string) (*xsd.Schema, *xsd.Attribute) {
+	attr, _, ok := t.symbols.attributes.get(schemaScope(t.c).resolve(name))
+	if !ok {
+		return nil, nil
 	}
 
-	if stripAliasNSFromType(element.Type) == stripAliasNSFromType(t.typeName) {
-		if len(t.foundElementName) == 0 {
-			t.foundElementName = element.Name
-			t.setNamespace(element.Type)
-			return
-		}
+	return t.symbols.schemas[attr], attr
+}
 
-		if t.foundElementName != element.Name {
-			// Duplicate t.typeName under different element names
-			t.typeUsageConflict = true
-			return
-		}
+func (t *xsdParser) getGlobalComplexType(name string) (*xsd.Schema, *xsd.ComplexType) {
+	ct, _, ok := t.symbols.complexTypes.get(t.symbols.typeName(schemaScope(t.c), name))
+	if !ok {
+		return nil, nil
+	}
+
+	return t.symbols.schemas[ct], ct
+}
+
+func (t *xsdParser) getGlobalGroup(name string) (*xsd.Schema, *xsd.Group) {
+	group, _, ok := t.symbols.groups.get(schemaScope(t.c).resolve(name))
+	if !ok {
+		return nil, nil
 	}
+
+	return t.symbols.schemas[group], group
 }
 
-func (t *xsdParser) setNamespace(typeName string) {
-	r := strings.Split(typeName, ":")
-	t.namespace = ""
-	if len(r) == 2 && r[0] != "xs" {
-		t.namespace = r[0] + ":"
-		t.foundElementName = t.namespace + t.foundElementName
-		t.typeName = t.namespace + t.typeName
+func (t *xsdParser) getGlobalAttributeGroup(name string) (*xsd.Schema, *xsd.AttributeGroup) {
+	group, _, ok := t.symbols.attributeGroups.get(schemaScope(t.c).resolve(name))
+	if !ok {
+		return nil, nil
 	}
+
+	return t.symbols.schemas[group], group
 }
 
-func (t *xsdParser) getGlobalAttribute(name string) *xsd.Attribute {
-	ref := t.buildQualifiedName(name)
+// requalify rewrites a prefixed name used in the schema from so that it keeps its meaning in the schema of the
+// parser, which happens when components are copied from one schema to another. The namespace is declared under a
+// new prefix when the schema of the parser has no prefix for it.
+func (t *xsdParser) requalify(name string, from *xsd.Schema) string {
+	if name == "" || from == nil || from == t.c {
+		return name
+	}
 
-	for _, schema := range t.all {
-		if schema.TargetNamespace == ref.Space {
-			for _, attr := range schema.Attributes {
-				if attr.Name == ref.Local {
-					return attr
-				}
+	qualifiedName := schemaScope(from).resolve(name)
+
+	for prefix, namespace := range t.c.Xmlns {
+		if namespace == qualifiedName.Space {
+			if prefix == "" {
+				return qualifiedName.Local
 			}
+			return prefix + ":" + qualifiedName.Local
 		}
 	}
 
-	return nil
-}
+	if _, ok := t.c.Xmlns[""]; !ok && qualifiedName.Space == t.c.TargetNamespace {
+		return qualifiedName.Local
+	}
 
-func (t *xsdParser) buildQualifiedName(name string) (qualifiedName xml.Name) {
-	x := strings.SplitN(name, ":", 2)
-	if len(x) == 1 {
-		qualifiedName.Local = x[0]
-		return
+	if t.c.Xmlns == nil {
+		t.c.Xmlns = make(map[string]string)
 	}
 
-	qualifiedName.Local = x[1]
-	qualifiedName.Space = x[0]
-	if ns, ok := t.c.Xmlns[qualifiedName.Space]; ok {
-		qualifiedName.Space = ns
+	prefix := "ns1"
+	for i := 2; t.c.Xmlns[prefix] != ""; i++ {
+		prefix = "ns" + strconv.Itoa(i)
 	}
-	return
+
+	t.c.Xmlns[prefix] = qualifiedName.Space
+	return prefix + ":" + qualifiedName.Local
 }
 
-func (t *xsdParser) initFindNameByType(name string) {
-	t.mode = findNameByType
-	t.typeName = stripAliasNSFromType(name)
-	t.foundElementName = ""
-	t.typeUsageConflict = false
+// importElements returns the elements of another schema as they read in the schema of the parser.
+func (t *xsdParser) importElements(elements []*xsd.Element, from *xsd.Schema) []*xsd.Element {
+	if from == t.c {
+		return elements
+	}
+
+	copies := copyElements(elements)
+	t.requalifyElements(copies, from)
+
+	return copies
 }
 
-func (t *xsdParser) findNameByType(name string) string {
-	t.initFindNameByType(name)
+// importAttributes returns the attributes of another schema as they read in the schema of the parser.
+func (t *xsdParser) importAttributes(attributes []*xsd.Attribute, from *xsd.Schema) []*xsd.Attribute {
+	if from == t.c {
+		return attributes
+	}
 
-	for _, schema := range t.all {
-		for _, elm := range schema.Elements {
-			t.parseElement(elm)
-		}
+	copies := make([]*xsd.Attribute, 0, len(attributes))
+	for _, attr := range attributes {
+		a := *attr
+		t.requalifyAttribute(&a, from)
+		copies = append(copies, &a)
+	}
 
-		for _, ct := range schema.ComplexTypes {
-			t.parseComplexType(ct)
-		}
+	return copies
+}
 
-		for _, st := range schema.SimpleType {
-			t.parseSimpleType(st)
+// requalifyElements rewrites the elements copied from another schema so that they keep their meaning in the schema
+// of the parser, local elements keeping the namespace and the form they have in the schema declaring them.
+func (t *xsdParser) requalifyElements(elements []*xsd.Element, from *xsd.Schema) {
+	for _, el := range elements {
+		if from != nil && from != t.c && el.Ref == "" && el.Namespace == "" {
+			el.Namespace = from.TargetNamespace
+			if from.QualifiesElement(el.Form) {
+				el.Form = "qualified"
+			} else {
+				el.Form = "unqualified"
+			}
 		}
-	}
 
-	// Return found element name if given type is used only once
-	if len(t.foundElementName) > 0 && !t.typeUsageConflict {
-		return t.foundElementName
+		el.Type = t.requalify(el.Type, from)
+		el.Ref = t.requalify(el.Ref, from)
 	}
+}
 
-	// Return original type name
-	// No element found or conflicting element names found
-	return t.typeName
+func (t *xsdParser) requalifyAttribute(attr *xsd.Attribute, from *xsd.Schema) {
+	attr.Type = t.requalify(attr.Type, from)
+	attr.Ref = t.requalify(attr.Ref, from)
 }
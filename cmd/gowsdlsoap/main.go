@@ -8,12 +8,24 @@ Usage: gowsdlsoap [clientOption] soapApi.wsdl
   -p string
         Package under which code will be generated (default "soapApi")
   -v    Shows gowsdlsoap version
+  -camel-case
+        Generates camel cased names, such as OrderID for order_id
+  -initialisms string
+        Comma separated initialisms camel cased names write in upper case, besides ID, URL, HTTP...
+  -bindings string
+        Bindings file renaming types, fields and operations, mapping types to Go types, adding tags and skipping types
+  -episode string
+        File where the episode of the generated package, listing its Go types for other builds, will be saved
+  -import-path string
+        Import path of the generated package, recorded by its episode
+  -episodes string
+        Comma separated episodes of packages to import the types of their namespaces from instead of generating them
 
 Features
 
-Supports only Document/Literal wrapped services, which are WS-I (http://ws-i.org/) compliant.
+Supports Document/Literal wrapped services, which are WS-I (http://ws-i.org/) compliant, Document/Literal bare, RPC/Literal and RPC/Encoded (SOAP Section 5) services.
 
-Supports WSDL 1.1, XML Schema 1.0, SOAP 1.1.
+Supports WSDL 1.1, WSDL 2.0, XML Schema 1.0, SOAP 1.1 and SOAP 1.2.
 
 Resolves external XML Schemas
 
@@ -39,12 +51,14 @@ import (
 	"flag"
 	"fmt"
 	"go/format"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/go-aegian/gowsdlsoap"
+	"github.com/go-aegian/gowsdlsoap/builder"
 )
 
 // Version is initialized in compilation time by go build.
@@ -59,6 +73,12 @@ var outFile = flag.String("o", "soap-proxy.go", "output file name for the the so
 var dir = flag.String("d", "./", "output directory of the soap proxy file")
 var insecure = flag.Bool("i", false, "skip TLS verification")
 var makePublic = flag.Bool("make-public", true, "generates go types with public/exported")
+var camelCase = flag.Bool("camel-case", false, "generates camel cased go names, such as OrderID for order_id")
+var initialisms = flag.String("initialisms", "", "comma separated initialisms camel cased go names write in upper case, besides ID, URL, HTTP...")
+var bindingsFile = flag.String("bindings", "", "bindings file customizing the names, go types and tags of the generated code")
+var episodeFile = flag.String("episode", "", "file where the episode of the generated package is saved for other builds to import its types")
+var importPath = flag.String("import-path", "", "import path of the generated package, recorded by its episode")
+var episodes = flag.String("episodes", "", "comma separated episodes of packages to import types from instead of generating them")
 
 func init() {
 	log.SetFlags(0)
@@ -89,12 +109,33 @@ func main() {
 		log.Fatalln("Output file cannot be the same wsdl file")
 	}
 
-	builder, err := gowsdlsoap.New(wsdlPath, *pkg, *insecure, *makePublic)
+	var options []builder.Option
+	if *camelCase {
+		options = append(options, builder.WithCamelCase(strings.FieldsFunc(*initialisms, func(r rune) bool { return r == ',' })...))
+	}
+
+	if *bindingsFile != "" {
+		options = append(options, builder.WithBindings(*bindingsFile))
+	}
+
+	if *episodeFile != "" {
+		if *importPath == "" {
+			log.Fatalln("The import path of the package is required to save its episode")
+		}
+
+		options = append(options, builder.WithEpisode(*importPath))
+	}
+
+	if *episodes != "" {
+		options = append(options, builder.WithEpisodes(strings.FieldsFunc(*episodes, func(r rune) bool { return r == ',' })...))
+	}
+
+	soapBuilder, err := gowsdlsoap.New(wsdlPath, *pkg, *insecure, *makePublic, options...)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	soapCode, err := builder.Build()
+	soapCode, err := soapBuilder.Build()
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -108,6 +149,12 @@ func main() {
 
 	writeFile(filepath.Join(pkg, strings.Replace(*outFile, ".", "_operations.", 1)), soapCode["operations"])
 
+	if *episodeFile != "" {
+		if err := ioutil.WriteFile(*episodeFile, soapCode["episode"], 0644); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
 	log.Println("Done")
 }
 
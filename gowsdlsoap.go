@@ -3,6 +3,6 @@ package gowsdlsoap
 import "github.com/go-aegian/gowsdlsoap/builder"
 
 // New creates the builder.
-func New(file, pkg string, ignoreTLS bool, exportAllTypes bool) (*builder.Builder, error) {
-	return builder.New(file, pkg, ignoreTLS, exportAllTypes)
+func New(file, pkg string, ignoreTLS bool, exportAllTypes bool, opt ...builder.Option) (*builder.Builder, error) {
+	return builder.New(file, pkg, ignoreTLS, exportAllTypes, opt...)
 }
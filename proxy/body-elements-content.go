@@ -0,0 +1,29 @@
+package proxy
+
+import (
+	"encoding/xml"
+
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
+)
+
+// bodyElementsContent wraps a message made of several body elements, so that each one is written as a direct
+// child of the SOAP body instead of being nested in an element named after the message.
+type bodyElementsContent struct {
+	message soap.BodyElements
+}
+
+// MarshalXML implements xml.Marshaler writing every element of the message, nil ones being skipped.
+func (c *bodyElementsContent) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	for _, element := range c.message.BodyElements() {
+		if err := e.EncodeElement(element.Value, xml.StartElement{Name: element.Name}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BodyElements implements soap.BodyElements, so that the elements of the message are found when they are written.
+func (c *bodyElementsContent) BodyElements() []soap.BodyElement {
+	return c.message.BodyElements()
+}
@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/xml"
@@ -49,6 +50,22 @@ func (s *Client) AddMIMEMultipartAttachment(attachment soap.MIMEMultipartAttachm
 	s.attachments = append(s.attachments, attachment)
 }
 
+// Clone returns a copy of the client with the given options applied on top of its own, leaving the client as it
+// is. Headers and attachments added to either of them afterwards are not seen by the other one.
+func (s *Client) Clone(opt ...Option) *Client {
+	opts := *s.opts
+	for _, o := range opt {
+		o(&opts)
+	}
+
+	return &Client{
+		url:         s.url,
+		opts:        &opts,
+		headers:     append([]interface{}(nil), s.headers...),
+		attachments: append([]soap.MIMEMultipartAttachment(nil), s.attachments...),
+	}
+}
+
 // SetHeaders sets envelope headers, overwriting any existing headers.
 // For correct behavior, every header must contain a `XMLName` field.  Refer to #121 for details
 func (s *Client) SetHeaders(headers ...interface{}) {
@@ -60,6 +77,25 @@ func (s *Client) CallContext(ctx context.Context, soapAction string, request, re
 	return s.call(ctx, soapAction, request, response, nil, nil)
 }
 
+// CallOneWayContext performs HTTP POST request for an operation without output message, following either the
+// in-only or the robust-in-only message exchange pattern. The server may reply with an empty body, or with a fault
+// which is then returned as the error.
+func (s *Client) CallOneWayContext(ctx context.Context, soapAction string, request interface{}) error {
+	return s.call(ctx, soapAction, request, &soap.OneWay{}, nil, nil)
+}
+
+// CallOptionalContext performs HTTP POST request for an operation following the in-optional-out message exchange
+// pattern. The server may reply with the response, with an empty body, or with a fault which is then returned as the
+// error. It tells whether the response was received.
+func (s *Client) CallOptionalContext(ctx context.Context, soapAction string, request, response interface{}) (bool, error) {
+	optional := &soap.OptionalResponse{Content: response}
+	if err := s.call(ctx, soapAction, request, optional, nil, nil); err != nil {
+		return false, err
+	}
+
+	return optional.Received, nil
+}
+
 // Call performs HTTP POST request.
 // Note that if the server returns a status code >= 400, a HTTPError will be returned
 func (s *Client) Call(soapAction string, request, response interface{}) error {
@@ -91,12 +127,22 @@ func (s *Client) call(ctx context.Context, soapAction string, request, response
 	retAttachments *[]soap.MIMEMultipartAttachment) error {
 
 	soapRequest := soap.NewEnvelope()
+	if s.opts.Soap12 {
+		soapRequest = soap.NewEnvelope12()
+	}
 
 	if s.headers != nil && len(s.headers) > 0 {
 		soapRequest.Header = &soap.Header{Headers: s.headers}
 	}
 
 	soapRequest.Body.Content = request
+	if message, ok := request.(soap.BodyElements); ok {
+		soapRequest.Body.Content = &bodyElementsContent{message: message}
+	}
+
+	if request != nil && s.opts.UnqualifiedElements && !s.opts.SoapEncoding {
+		soapRequest.Body.Content = &unqualifiedContent{Content: soapRequest.Body.Content}
+	}
 
 	buffer := new(bytes.Buffer)
 
@@ -109,6 +155,8 @@ func (s *Client) call(ctx context.Context, soapAction string, request, response
 		encoder = newMtomEncoder(buffer)
 	} else if s.opts.Mma {
 		encoder = newMmaEncoder(buffer, s.attachments)
+	} else if s.opts.SoapEncoding {
+		encoder = newEncodedEncoder(buffer)
 	} else {
 		encoder = xml.NewEncoder(buffer)
 	}
@@ -136,11 +184,18 @@ func (s *Client) call(ctx context.Context, soapAction string, request, response
 		httpRequest.Header.Add(soap.ContentTypeHeader, fmt.Sprintf(soap.MtomContentType, encoder.(*mtomEncoder).Boundary()))
 	} else if s.opts.Mma {
 		httpRequest.Header.Add(soap.ContentTypeHeader, fmt.Sprintf(mmaContentType, encoder.(*mmaEncoder).Boundary()))
+	} else if s.opts.Soap12 && soapAction != "" {
+		httpRequest.Header.Add(soap.ContentTypeHeader, fmt.Sprintf("%s; action=\"%s\"", soap.Soap12ContentType, soapAction))
+	} else if s.opts.Soap12 {
+		httpRequest.Header.Add(soap.ContentTypeHeader, soap.Soap12ContentType)
 	} else {
-		httpRequest.Header.Add(soap.ContentTypeHeader, "text/xml; charset=\"utf-8\"")
+		httpRequest.Header.Add(soap.ContentTypeHeader, soap.SoapContentType)
+	}
+
+	if !s.opts.Soap12 {
+		httpRequest.Header.Add("SOAPAction", soapAction)
 	}
 
-	httpRequest.Header.Add("SOAPAction", soapAction)
 	httpRequest.Header.Set("User-Agent", "gowsdlsoap/1.0")
 
 	if s.opts.HttpHeaders != nil {
@@ -188,12 +243,24 @@ func (s *Client) call(ctx context.Context, soapAction string, request, response
 		}
 	}
 
+	body := bufio.NewReader(res.Body)
+	switch response.(type) {
+	case *soap.OneWay, *soap.OptionalResponse:
+		if _, err := body.Peek(1); err == io.EOF {
+			// nothing but an acknowledgement for an operation which may not reply
+			return nil
+		}
+	}
+
 	soapResponse := soap.NewEnvelopeResponse()
 	soapResponse.Body = soap.BodyResponse{
 		Content: response,
 		Fault: &soap.Fault{
 			Detail: faultDetail,
 		},
+		Fault12: &soap.Fault12{
+			Detail: faultDetail,
+		},
 	}
 
 	defer func(msg interface{}) {
@@ -223,11 +290,13 @@ func (s *Client) call(ctx context.Context, soapAction string, request, response
 
 	var dec soap.Decoder
 	if mtomBoundary != "" {
-		dec = newMtomDecoder(res.Body, mtomBoundary)
+		dec = newMtomDecoder(body, mtomBoundary)
 	} else if mmaBoundary != "" {
-		dec = newMmaDecoder(res.Body, mmaBoundary)
+		dec = newMmaDecoder(body, mmaBoundary)
+	} else if s.opts.SoapEncoding {
+		dec = newEncodedDecoder(body)
 	} else {
-		dec = xml.NewDecoder(res.Body)
+		dec = xml.NewDecoder(body)
 	}
 
 	if err := dec.Decode(soapResponse); err != nil {
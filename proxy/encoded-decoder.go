@@ -0,0 +1,367 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
+)
+
+// encodedDecoder decodes SOAP Section 5 encoded messages. The message is first read into a tree, where href
+// references are replaced by the multiRef values they point to and soapenc:Array items are turned into repeated
+// accessors, so the result can be decoded into the generated literal structs.
+type encodedDecoder struct {
+	reader io.Reader
+}
+
+// encodedNode is an element, or a text node when name.Local is empty, keeping the raw prefixes of the message.
+// The document itself is the only node without a parent.
+type encodedNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	text     string
+	children []*encodedNode
+	parent   *encodedNode
+}
+
+func newEncodedDecoder(r io.Reader) *encodedDecoder {
+	return &encodedDecoder{reader: r}
+}
+
+func (d *encodedDecoder) Decode(v interface{}) error {
+	root, err := readEncodedNodes(d.reader)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]*encodedNode)
+	root.collectIds(ids)
+
+	referenced := make(map[*encodedNode]bool)
+	root.resolveReferences(ids, referenced, make(map[*encodedNode]bool))
+	root.removeMultiRefs(referenced)
+	root.flattenArrays()
+
+	buffer := new(bytes.Buffer)
+	root.write(buffer)
+
+	return xml.NewDecoder(buffer).Decode(v)
+}
+
+func readEncodedNodes(r io.Reader) (*encodedNode, error) {
+	decoder := xml.NewDecoder(r)
+	document := &encodedNode{}
+	current := document
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &encodedNode{name: t.Name, attrs: t.Copy().Attr, parent: current}
+			current.children = append(current.children, node)
+			current = node
+
+		case xml.EndElement:
+			if current.parent == nil {
+				return nil, xml.UnmarshalError("unexpected end element </" + t.Name.Local + ">")
+			}
+			current = current.parent
+
+		case xml.CharData:
+			current.children = append(current.children, &encodedNode{text: string(t), parent: current})
+		}
+	}
+
+	return document, nil
+}
+
+func (n *encodedNode) isText() bool {
+	return n.name.Local == "" && n.parent != nil
+}
+
+func (n *encodedNode) attr(local string) (string, bool) {
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// lookup resolves a prefix into its namespace, walking up the declarations in scope.
+func (n *encodedNode) lookup(prefix string) string {
+	for node := n; node != nil; node = node.parent {
+		for _, attr := range node.attrs {
+			if prefix == "" && attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+				return attr.Value
+			}
+
+			if prefix != "" && attr.Name.Space == "xmlns" && attr.Name.Local == prefix {
+				return attr.Value
+			}
+		}
+	}
+
+	return ""
+}
+
+// encodingAttr returns the value of an attribute qualified by the SOAP 1.1 or 1.2 encoding namespace.
+func (n *encodedNode) encodingAttr(local string) (string, bool) {
+	for _, attr := range n.attrs {
+		if attr.Name.Local == local && attr.Name.Space != "" && isEncodingNamespace(n.lookup(attr.Name.Space)) {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// id returns the identifier of a value, given by the id attribute in SOAP 1.1 and by enc:id in SOAP 1.2.
+func (n *encodedNode) id() (string, bool) {
+	if id, ok := n.attr("id"); ok {
+		return id, true
+	}
+
+	return n.encodingAttr("id")
+}
+
+// reference returns the identifier of the value an accessor refers to, given by href="#id" in SOAP 1.1 and by
+// enc:ref="id" in SOAP 1.2.
+func (n *encodedNode) reference() (string, bool) {
+	if href, ok := n.attr("href"); ok && strings.HasPrefix(href, "#") {
+		return href[1:], true
+	}
+
+	return n.encodingAttr("ref")
+}
+
+// isEncodingAttr tells whether an attribute is one of the given attributes of the encoding, unqualified when unqualified
+// is given and qualified by the SOAP 1.1 or 1.2 encoding namespace when qualified is.
+func (n *encodedNode) isEncodingAttr(attr xml.Attr, unqualified string, qualified ...string) bool {
+	if attr.Name.Space == "" {
+		return attr.Name.Local == unqualified
+	}
+
+	for _, local := range qualified {
+		if attr.Name.Local == local && isEncodingNamespace(n.lookup(attr.Name.Space)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEncodingNamespace(namespace string) bool {
+	return namespace == soap.XmlNsSoapEnc || namespace == soap.XmlNsSoap12Enc
+}
+
+func (n *encodedNode) isBody() bool {
+	return n.name.Local == "Body" && (n.lookup(n.name.Space) == soap.XmlNsSoapEnv || n.lookup(n.name.Space) == soap.XmlNsSoap12Env)
+}
+
+func (n *encodedNode) isArray() bool {
+	for _, name := range []string{"arrayType", "itemType", "arraySize"} {
+		if _, ok := n.encodingAttr(name); ok {
+			return true
+		}
+	}
+
+	for _, attr := range n.attrs {
+
+		if attr.Name.Local == "type" && n.lookup(attr.Name.Space) == soap.XmlNsSoapXsi {
+			prefix, local := "", attr.Value
+			if i := strings.Index(attr.Value, ":"); i >= 0 {
+				prefix, local = attr.Value[:i], attr.Value[i+1:]
+			}
+
+			if local == "Array" && isEncodingNamespace(n.lookup(prefix)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (n *encodedNode) collectIds(ids map[string]*encodedNode) {
+	if id, ok := n.id(); ok && !n.isText() {
+		ids[id] = n
+	}
+
+	for _, child := range n.children {
+		child.collectIds(ids)
+	}
+}
+
+// resolveReferences replaces the content of every accessor referring to a value with the content of that value.
+// Cyclic graphs can't be represented as a tree, so a reference back to a value being resolved is left untouched.
+func (n *encodedNode) resolveReferences(ids map[string]*encodedNode, referenced, resolving map[*encodedNode]bool) {
+	if ref, ok := n.reference(); ok {
+		if target, found := ids[ref]; found && !resolving[target] {
+			referenced[target] = true
+			resolving[target] = true
+			target.resolveReferences(ids, referenced, resolving)
+			delete(resolving, target)
+
+			attrs := make([]xml.Attr, 0, len(n.attrs)+len(target.attrs))
+			for _, attr := range n.attrs {
+				if !n.isEncodingAttr(attr, "href", "ref") {
+					attrs = append(attrs, attr)
+				}
+			}
+
+			for _, attr := range target.attrs {
+				if target.isEncodingAttr(attr, "id", "id", "root") {
+					continue
+				}
+				attrs = appendAttr(attrs, attr)
+			}
+
+			n.attrs = attrs
+			n.children = nil
+			for _, child := range target.children {
+				n.children = append(n.children, child.clone(n))
+			}
+		}
+	}
+
+	for _, child := range n.children {
+		child.resolveReferences(ids, referenced, resolving)
+	}
+}
+
+// removeMultiRefs drops the independent elements of the body once their content has been inlined, along with
+// any other element flagged with soapenc:root="0".
+func (n *encodedNode) removeMultiRefs(referenced map[*encodedNode]bool) {
+	if n.isBody() {
+		children := n.children[:0]
+		for _, child := range n.children {
+			if referenced[child] || child.isSerializationRoot() {
+				continue
+			}
+			children = append(children, child)
+		}
+
+		n.children = children
+		return
+	}
+
+	for _, child := range n.children {
+		child.removeMultiRefs(referenced)
+	}
+}
+
+func (n *encodedNode) isSerializationRoot() bool {
+	for _, attr := range n.attrs {
+		if attr.Name.Local == "root" && attr.Value == "0" && isEncodingNamespace(n.lookup(attr.Name.Space)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flattenArrays turns every soapenc:Array accessor into one accessor per item, which is how encoding/xml decodes slices.
+func (n *encodedNode) flattenArrays() {
+	children := make([]*encodedNode, 0, len(n.children))
+
+	for _, child := range n.children {
+		if child.isText() || !child.isArray() {
+			children = append(children, child)
+			continue
+		}
+
+		for _, item := range child.children {
+			if item.isText() {
+				continue
+			}
+
+			flattened := item.clone(n)
+			flattened.name = child.name
+
+			// keep the namespace declarations of the array, its items may rely on them
+			for _, attr := range child.attrs {
+				if attr.Name.Space == "xmlns" || attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+					flattened.attrs = appendAttr(flattened.attrs, attr)
+				}
+			}
+
+			children = append(children, flattened)
+		}
+	}
+
+	n.children = children
+
+	for _, child := range n.children {
+		child.flattenArrays()
+	}
+}
+
+// appendAttr appends an attribute unless one with the same name is already present.
+func appendAttr(attrs []xml.Attr, attr xml.Attr) []xml.Attr {
+	for _, a := range attrs {
+		if a.Name == attr.Name {
+			return attrs
+		}
+	}
+
+	return append(attrs, attr)
+}
+
+func (n *encodedNode) clone(parent *encodedNode) *encodedNode {
+	c := &encodedNode{name: n.name, text: n.text, parent: parent}
+	c.attrs = append(c.attrs, n.attrs...)
+
+	for _, child := range n.children {
+		c.children = append(c.children, child.clone(c))
+	}
+
+	return c
+}
+
+func (n *encodedNode) write(w *bytes.Buffer) {
+	if n.isText() {
+		// text outside the root element is dropped
+		if n.parent.parent != nil {
+			_ = xml.EscapeText(w, []byte(n.text))
+		}
+		return
+	}
+
+	if n.parent != nil {
+		w.WriteString("<" + rawName(n.name))
+		for _, attr := range n.attrs {
+			w.WriteString(" " + rawName(attr.Name) + `="`)
+			_ = xml.EscapeText(w, []byte(attr.Value))
+			w.WriteString(`"`)
+		}
+		w.WriteString(">")
+	}
+
+	for _, child := range n.children {
+		child.write(w)
+	}
+
+	if n.parent != nil {
+		w.WriteString("</" + rawName(n.name) + ">")
+	}
+}
+
+func rawName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
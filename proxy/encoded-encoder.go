@@ -0,0 +1,533 @@
+package proxy
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
+	"github.com/go-aegian/gowsdlsoap/builder/xsd"
+)
+
+// nillable is implemented by xsd.Nillable, whatever the type of its value.
+type nillable interface {
+	IsAbsent() bool
+	Interface() interface{}
+}
+
+type encodedEncoder struct {
+	encoder *xml.Encoder
+}
+
+// encodedContent wraps the body content so that it is written using SOAP Section 5 encoding. The encodingStyle
+// attribute is qualified by the namespace of the envelope, which is declared with envelopePrefix.
+type encodedContent struct {
+	value    interface{}
+	envelope string
+}
+
+// encodedWriter collects the tokens of an encoded value, along with the namespaces its xsi:type values refer to.
+// encoding is the SOAP encoding namespace of the envelope version, declared with the soapenc prefix.
+type encodedWriter struct {
+	tokens     []xml.Token
+	prefixes   map[string]string
+	namespaces *[]string
+	encoding   string
+}
+
+var (
+	xsiTyperType      = reflect.TypeOf((*soap.XSITyper)(nil)).Elem()
+	marshalerAttrType = reflect.TypeOf((*xml.MarshalerAttr)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	xmlNameType       = reflect.TypeOf(xml.Name{})
+	xsdDateTimeType   = reflect.TypeOf(xsd.DateTime{})
+	xsdDateType       = reflect.TypeOf(xsd.Date{})
+	xsdTimeType       = reflect.TypeOf(xsd.Time{})
+	encodedKindToXsd  = map[reflect.Kind]string{
+		reflect.String:  "string",
+		reflect.Bool:    "boolean",
+		reflect.Int:     "long",
+		reflect.Int8:    "byte",
+		reflect.Int16:   "short",
+		reflect.Int32:   "int",
+		reflect.Int64:   "long",
+		reflect.Uint:    "unsignedLong",
+		reflect.Uint8:   "unsignedByte",
+		reflect.Uint16:  "unsignedShort",
+		reflect.Uint32:  "unsignedInt",
+		reflect.Uint64:  "unsignedLong",
+		reflect.Float32: "float",
+		reflect.Float64: "double",
+	}
+	envelopePrefix = tagPrefix(reflect.TypeOf(soap.Envelope{}), "XMLName")
+)
+
+func newEncodedEncoder(w io.Writer) *encodedEncoder {
+	return &encodedEncoder{encoder: xml.NewEncoder(w)}
+}
+
+func (e *encodedEncoder) Encode(v interface{}) error {
+	if envelope, ok := v.(*soap.Envelope); ok && envelope.Body.Content != nil {
+		envelope.Body.Content = &encodedContent{value: envelope.Body.Content, envelope: envelope.XMLNS}
+	}
+
+	return e.encoder.Encode(v)
+}
+
+func (e *encodedEncoder) Flush() error {
+	return e.encoder.Flush()
+}
+
+// MarshalXML implements xml.Marshaler writing the wrapped value with xsi:type annotations, soapenc:Array
+// for slices and the encodingStyle attribute on the root element.
+func (c *encodedContent) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	if content, ok := c.value.(*bodyElementsContent); ok {
+		for _, element := range content.message.BodyElements() {
+			if err := c.marshalEncoded(e, element.Name, reflect.ValueOf(element.Value)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return c.marshalEncoded(e, xml.Name{}, reflect.ValueOf(c.value))
+}
+
+// marshalEncoded writes one root element of the body, named after the value itself when name is empty.
+func (c *encodedContent) marshalEncoded(e *xml.Encoder, name xml.Name, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if name.Local == "" {
+		name = rootName(v)
+	}
+
+	encoding := soap.XmlNsSoapEnc
+	if c.envelope == soap.XmlNsSoap12Env {
+		encoding = soap.XmlNsSoap12Enc
+	}
+
+	w := &encodedWriter{prefixes: make(map[string]string), namespaces: new([]string), encoding: encoding}
+	if err := w.writeElement(name, v, true); err != nil {
+		return err
+	}
+
+	root := w.tokens[0].(xml.StartElement)
+	root.Attr = append(root.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:soapenc"}, Value: encoding},
+		xml.Attr{Name: xml.Name{Local: envelopePrefix + ":encodingStyle"}, Value: encoding},
+	)
+
+	for _, ns := range *w.namespaces {
+		root.Attr = append(root.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + w.prefixes[ns]}, Value: ns})
+	}
+
+	w.tokens[0] = root
+
+	for _, token := range w.tokens {
+		if err := e.EncodeToken(token); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rootName(v reflect.Value) xml.Name {
+	if v.Kind() == reflect.Struct {
+		if field, ok := v.Type().FieldByName("XMLName"); ok && field.Type == xmlNameType {
+			if name := v.FieldByIndex(field.Index).Interface().(xml.Name); name.Local != "" {
+				return name
+			}
+
+			if tag := strings.Split(field.Tag.Get("xml"), ",")[0]; tag != "" {
+				return parseTagName(tag)
+			}
+		}
+	}
+
+	return xml.Name{Local: v.Type().Name()}
+}
+
+// tagPrefix returns the prefix the xml tag of a struct field gives to its name.
+func tagPrefix(t reflect.Type, name string) string {
+	field, _ := t.FieldByName(name)
+	tag := strings.Split(field.Tag.Get("xml"), ",")[0]
+
+	if i := strings.Index(tag, ":"); i >= 0 {
+		return tag[:i]
+	}
+
+	return ""
+}
+
+func parseTagName(tag string) xml.Name {
+	if i := strings.LastIndex(tag, " "); i >= 0 {
+		return xml.Name{Space: tag[:i], Local: tag[i+1:]}
+	}
+
+	return xml.Name{Local: tag}
+}
+
+// qualify returns the prefixed representation of a type name, declaring its namespace when needed.
+func (w *encodedWriter) qualify(name xml.Name) string {
+	switch name.Space {
+	case "":
+		return name.Local
+	case soap.XmlNsSoapXsd:
+		return "xsd:" + name.Local
+	case w.encoding:
+		return "soapenc:" + name.Local
+	}
+
+	prefix, ok := w.prefixes[name.Space]
+	if !ok {
+		prefix = fmt.Sprintf("ns%d", len(w.prefixes)+1)
+		w.prefixes[name.Space] = prefix
+		*w.namespaces = append(*w.namespaces, name.Space)
+	}
+
+	return prefix + ":" + name.Local
+}
+
+// typeName returns the xsi:type of a value, or an empty string when it can't be determined.
+func (w *encodedWriter) typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Implements(xsiTyperType) {
+		return w.qualify(reflect.Zero(t).Interface().(soap.XSITyper).XSIType())
+	}
+
+	if reflect.PtrTo(t).Implements(xsiTyperType) {
+		return w.qualify(reflect.New(t).Interface().(soap.XSITyper).XSIType())
+	}
+
+	switch {
+	case t == xsdDateTimeType:
+		return "xsd:dateTime"
+	case t == xsdDateType:
+		return "xsd:date"
+	case t == xsdTimeType:
+		return "xsd:time"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "xsd:base64Binary"
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return "soapenc:Array"
+	}
+
+	if xsdType, ok := encodedKindToXsd[t.Kind()]; ok {
+		return "xsd:" + xsdType
+	}
+
+	return ""
+}
+
+func (w *encodedWriter) writeElement(name xml.Name, v reflect.Value, root bool) error {
+	// Qualified names are written with an explicit prefix, so unqualified accessors don't inherit a default namespace.
+	start := xml.StartElement{Name: xml.Name{Local: w.qualify(name)}}
+
+	if n, ok := asNillable(v); ok {
+		v = reflect.ValueOf(n.Interface())
+		if !v.IsValid() {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"})
+			w.tokens = append(w.tokens, start, start.End())
+			return nil
+		}
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"})
+			w.tokens = append(w.tokens, start, start.End())
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !root {
+		if typeName := w.typeName(v.Type()); typeName != "" {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xsi:type"}, Value: typeName})
+		}
+	}
+
+	if isEncodedArray(v) {
+		return w.writeArray(start, v)
+	}
+
+	if v.Kind() == reflect.Struct && !isEncodedText(v) {
+		return w.writeStruct(start, v)
+	}
+
+	text, err := encodedText(v)
+	if err != nil {
+		return err
+	}
+
+	w.tokens = append(w.tokens, start, xml.CharData(text), start.End())
+
+	return nil
+}
+
+func (w *encodedWriter) writeArray(start xml.StartElement, v reflect.Value) error {
+	itemType := w.typeName(v.Type().Elem())
+	if itemType == "" {
+		itemType = "xsd:anyType"
+	}
+
+	if w.encoding == soap.XmlNsSoap12Enc {
+		start.Attr = append(start.Attr,
+			xml.Attr{Name: xml.Name{Local: "soapenc:itemType"}, Value: itemType},
+			xml.Attr{Name: xml.Name{Local: "soapenc:arraySize"}, Value: strconv.Itoa(v.Len())},
+		)
+	} else {
+		start.Attr = append(start.Attr, xml.Attr{
+			Name:  xml.Name{Local: "soapenc:arrayType"},
+			Value: fmt.Sprintf("%s[%d]", itemType, v.Len()),
+		})
+	}
+
+	w.tokens = append(w.tokens, start)
+
+	for i := 0; i < v.Len(); i++ {
+		if err := w.writeElement(xml.Name{Local: "item"}, v.Index(i), false); err != nil {
+			return err
+		}
+	}
+
+	w.tokens = append(w.tokens, start.End())
+
+	return nil
+}
+
+func (w *encodedWriter) writeStruct(start xml.StartElement, v reflect.Value) error {
+	var (
+		children []xml.Token
+		text     string
+	)
+
+	err := w.writeFields(&start, &children, &text, v)
+	if err != nil {
+		return err
+	}
+
+	w.tokens = append(w.tokens, start)
+	if text != "" {
+		w.tokens = append(w.tokens, xml.CharData(text))
+	}
+	w.tokens = append(w.tokens, children...)
+	w.tokens = append(w.tokens, start.End())
+
+	return nil
+}
+
+// writeFields writes the fields of a struct, flattening anonymous embedded base types.
+func (w *encodedWriter) writeFields(start *xml.StartElement, children *[]xml.Token, text *string, v reflect.Value) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+
+		if field.PkgPath != "" && !field.Anonymous || field.Name == "XMLName" {
+			continue
+		}
+
+		tag := field.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+
+		tagName, flags := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			tagName, flags = tag[:i], tag[i+1:]
+		}
+
+		if field.Anonymous && tagName == "" && flags == "" {
+			for fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					break
+				}
+				fieldValue = fieldValue.Elem()
+			}
+
+			if fieldValue.Kind() == reflect.Struct {
+				if err := w.writeFields(start, children, text, fieldValue); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		omitEmpty := strings.Contains(flags, "omitempty")
+		if omitEmpty && isEmptyValue(fieldValue) {
+			continue
+		}
+
+		if n, ok := asNillable(fieldValue); ok && n.IsAbsent() {
+			continue
+		}
+
+		switch {
+		case strings.Contains(flags, "attr") && strings.Contains(flags, "any"):
+			if attrs, ok := fieldValue.Interface().([]xml.Attr); ok {
+				start.Attr = append(start.Attr, attrs...)
+			}
+
+		case strings.Contains(flags, "attr"):
+			if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
+				continue
+			}
+
+			value, err := encodedText(reflect.Indirect(fieldValue))
+			if err != nil {
+				return err
+			}
+
+			if tagName == "" {
+				tagName = field.Name
+			}
+
+			start.Attr = append(start.Attr, xml.Attr{Name: parseTagName(tagName), Value: value})
+
+		case strings.Contains(flags, "chardata"):
+			value, err := encodedText(reflect.Indirect(fieldValue))
+			if err != nil {
+				return err
+			}
+
+			*text += value
+
+		case strings.Contains(flags, "innerxml") || strings.Contains(flags, "comment"):
+			continue
+
+		default:
+			if tagName == "" {
+				tagName = field.Name
+			}
+
+			parents := strings.Split(tagName, ">")
+			name := parseTagName(parents[len(parents)-1])
+			parents = parents[:len(parents)-1]
+
+			child := &encodedWriter{prefixes: w.prefixes, namespaces: w.namespaces, encoding: w.encoding}
+			for _, parent := range parents {
+				child.tokens = append(child.tokens, xml.StartElement{Name: xml.Name{Local: parent}})
+			}
+
+			if err := child.writeElement(name, fieldValue, false); err != nil {
+				return err
+			}
+
+			for i := len(parents) - 1; i >= 0; i-- {
+				child.tokens = append(child.tokens, xml.EndElement{Name: xml.Name{Local: parents[i]}})
+			}
+
+			*children = append(*children, child.tokens...)
+		}
+	}
+
+	return nil
+}
+
+// asNillable returns the value as a nillable element when it is one.
+func asNillable(v reflect.Value) (nillable, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+
+	n, ok := v.Interface().(nillable)
+	return n, ok
+}
+
+// isEncodedArray reports whether a value is written as a SOAP array, unlike xsd:list values which are written as
+// text.
+func isEncodedArray(v reflect.Value) bool {
+	if v.Type().Implements(marshalerAttrType) {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Array:
+		return true
+	}
+
+	return false
+}
+
+// isEncodedText reports whether a struct value is written as text, like the xsd date and time types.
+func isEncodedText(v reflect.Value) bool {
+	t := v.Type()
+	return t.Implements(marshalerAttrType) || reflect.PtrTo(t).Implements(marshalerAttrType) ||
+		t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+// encodedText returns the lexical representation of a simple value.
+func encodedText(v reflect.Value) (string, error) {
+	if !v.IsValid() {
+		return "", nil
+	}
+
+	if v.CanInterface() {
+		switch m := v.Interface().(type) {
+		case xml.MarshalerAttr:
+			attr, err := m.MarshalXMLAttr(xml.Name{})
+			return attr.Value, err
+		case encoding.TextMarshaler:
+			text, err := m.MarshalText()
+			return string(text), err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+	}
+
+	return "", fmt.Errorf("soap encoding: unsupported type %s", v.Type())
+}
+
+// isEmptyValue mirrors the omitempty semantics of encoding/xml.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
@@ -152,6 +152,19 @@ func (e *mtomEncoder) Flush() error {
 }
 
 func getBinaryFields(data interface{}, fields *[]reflect.Value) {
+	// the elements of a message made of several body elements are not fields of the value holding it
+	if message, ok := data.(soap.BodyElements); ok {
+		for _, element := range message.BodyElements() {
+			if v := reflect.ValueOf(element.Value); v.Kind() == reflect.Ptr && v.Elem().Type() == reflect.TypeOf((*Binary)(nil)) {
+				*fields = append(*fields, v.Elem())
+			} else {
+				getBinaryFields(element.Value, fields)
+			}
+		}
+
+		return
+	}
+
 	v := reflect.Indirect(reflect.ValueOf(data))
 
 	if v.Kind() != reflect.Struct {
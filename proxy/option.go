@@ -14,6 +14,9 @@ type Options struct {
 	HttpHeaders         map[string]string
 	Mtom                bool
 	Mma                 bool
+	Soap12              bool
+	SoapEncoding        bool
+	UnqualifiedElements bool
 	Timeout             time.Duration
 	ConnectionTimeout   time.Duration
 	TlsHandshakeTimeout time.Duration
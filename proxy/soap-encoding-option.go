@@ -0,0 +1,12 @@
+package proxy
+
+// WithSOAPEncoding is an Option to send and receive SOAP Section 5 (rpc/encoded) messages.
+//
+// Values are written with xsi:type annotations and slices as soapenc:Array, while responses
+// have their href/multiRef graphs and arrays resolved before being decoded. The SOAP 1.2
+// encoding is used along with WithSOAP12.
+func WithSOAPEncoding() Option {
+	return func(o *Options) {
+		o.SoapEncoding = true
+	}
+}
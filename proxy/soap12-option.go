@@ -0,0 +1,11 @@
+package proxy
+
+// WithSOAP12 is an Option to send requests as SOAP 1.2 messages.
+//
+// The envelope uses the 2003/05 namespace, the action travels in the Content-Type header
+// and faults are decoded as soap.Fault12.
+func WithSOAP12() Option {
+	return func(o *Options) {
+		o.Soap12 = true
+	}
+}
@@ -0,0 +1,79 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+
+	"github.com/go-aegian/gowsdlsoap/builder/soap"
+)
+
+// unqualifiedContent wraps the content of the SOAP body so that unqualified elements, which encoding/xml writes
+// without a namespace, are taken out of the default namespace of their parent by an empty xmlns declaration.
+type unqualifiedContent struct {
+	Content interface{}
+}
+
+// MarshalXML implements xml.Marshaler writing the content as encoding/xml does in the SOAP body, elements in no
+// namespace being written with xmlns="" when a default namespace is in scope.
+func (c *unqualifiedContent) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	data, err := xml.Marshal(&soap.Body{Content: c.Content})
+	if err != nil {
+		return err
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+
+	// the body is read for the content to be named as it would be in it, but is not written again
+	if _, err := d.RawToken(); err != nil {
+		return err
+	}
+
+	// the default namespaces in scope, the SOAP body having none
+	defaults := []string{""}
+
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			namespace, declared := defaults[len(defaults)-1], false
+
+			attrs := make([]xml.Attr, 0, len(t.Attr)+1)
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+					namespace, declared = attr.Value, true
+				}
+
+				attrs = append(attrs, xml.Attr{Name: xml.Name{Local: rawName(attr.Name)}, Value: attr.Value})
+			}
+
+			if t.Name.Space == "" && !declared && namespace != "" {
+				attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: ""})
+				namespace = ""
+			}
+
+			defaults = append(defaults, namespace)
+			token = xml.StartElement{Name: xml.Name{Local: rawName(t.Name)}, Attr: attrs}
+
+		case xml.EndElement:
+			if len(defaults) == 1 {
+				return nil
+			}
+
+			defaults = defaults[:len(defaults)-1]
+			token = xml.EndElement{Name: xml.Name{Local: rawName(t.Name)}}
+		}
+
+		if err := e.EncodeToken(token); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package proxy
+
+// WithUnqualifiedElements is an Option to write the elements in no namespace of a request out of the
+// default namespace of their parent.
+//
+// encoding/xml writes them without any namespace declaration, so they would be read as part of the
+// namespace of a qualified parent. The request is then written twice, the second time with xmlns=""
+// on those elements. Generated services set it up when their schemas declare unqualified elements.
+func WithUnqualifiedElements() Option {
+	return func(o *Options) {
+		o.UnqualifiedElements = true
+	}
+}
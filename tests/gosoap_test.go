@@ -2,12 +2,15 @@ package tests
 
 import (
 	"bytes"
+	"encoding/xml"
 	"errors"
 	"go/ast"
 	"go/format"
+	"go/importer"
 	"go/parser"
 	"go/printer"
 	"go/token"
+	"go/types"
 	"io/ioutil"
 	"log"
 	"os"
@@ -17,6 +20,8 @@ import (
 	"testing"
 
 	"github.com/go-aegian/gowsdlsoap"
+	"github.com/go-aegian/gowsdlsoap/builder"
+	"github.com/go-aegian/gowsdlsoap/builder/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -237,6 +242,811 @@ func TestEWSWSDL(t *testing.T) {
 	}
 }
 
+func TestSamplesGenerateCodeThatCompiles(t *testing.T) {
+	samples := []struct {
+		file    string
+		options []builder.Option
+	}{
+		{file: `wsdl-samples/soap12.wsdl`},
+		{file: `wsdl-samples/rpc-literal.wsdl`},
+		{file: `wsdl-samples/rpc-encoded.wsdl`},
+		{file: `wsdl-samples/document-bare.wsdl`},
+		{file: `wsdl-samples/wcf/service.wsdl`},
+		{file: `wsdl-samples/wcf-prefixes/service.wsdl`},
+		{file: `wsdl-samples/same-names/service.wsdl`},
+		{file: `wsdl-samples/wsdl20.wsdl`},
+		{file: `wsdl-samples/groups.wsdl`},
+		{file: `wsdl-samples/restrictions.wsdl`},
+		{file: `wsdl-samples/particles.wsdl`},
+		{file: `wsdl-samples/occurrences.wsdl`},
+		{file: `wsdl-samples/lists.wsdl`},
+		{file: `wsdl-samples/unions.wsdl`},
+		{file: `wsdl-samples/defaults.wsdl`},
+		{file: `wsdl-samples/mixed.wsdl`},
+		{file: `wsdl-samples/wildcards.wsdl`},
+		{file: `wsdl-samples/qualification.wsdl`},
+		{file: `wsdl-samples/polymorphism.wsdl`},
+		{file: `wsdl-samples/substitution-groups.wsdl`},
+		{file: `wsdl-samples/namespaces.wsdl`},
+		{file: `wsdl-samples/naming.wsdl`},
+		{file: `wsdl-samples/naming.wsdl`, options: []builder.Option{builder.WithCamelCase("SKU")}},
+		{file: `wsdl-samples/bindings.wsdl`, options: []builder.Option{builder.WithBindings(`wsdl-samples/bindings.xml`)}},
+		{file: `wsdl-samples/episode-common.wsdl`, options: []builder.Option{builder.WithEpisode("example.com/soap/common")}},
+	}
+
+	fileSet := token.NewFileSet()
+	config := types.Config{Importer: importer.ForCompiler(fileSet, "source", nil)}
+
+	for _, sample := range samples {
+		g, err := gowsdlsoap.New(sample.file, "soapApi", false, true, sample.options...)
+		assert.NoError(t, err)
+
+		resp, err := g.Build()
+		if !assert.NoError(t, err, sample.file) {
+			continue
+		}
+
+		var files []*ast.File
+		for _, name := range []string{"header", "types", "operations"} {
+			f, err := parser.ParseFile(fileSet, name+".go", resp[name], 0)
+			if assert.NoError(t, err, sample.file) {
+				files = append(files, f)
+			}
+		}
+
+		_, err = config.Check("soapApi", fileSet, files, nil)
+		assert.NoError(t, err, sample.file)
+	}
+}
+
+func TestSOAP12BindingGeneratesSOAP12Client(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/soap12.wsdl`)
+
+	service := pkg.Services[0]
+	assert.True(t, service.SOAP12)
+	assert.Equal(t, []string{"proxy.WithSOAP12()", "proxy.WithUnqualifiedElements()"}, service.ClientOptions())
+	assert.Equal(t, "http://example.com/GetLastTradePrice", service.Operations[0].Action)
+}
+
+func TestRPCLiteralGeneratesWrapperElements(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/rpc-literal.wsdl`)
+
+	getItem, restock := pkg.Services[0].Operations[0], pkg.Services[0].Operations[1]
+	assert.Equal(t, "GetItem", getItem.Request)
+	assert.Equal(t, "GetItemResponse", getItem.Response)
+	assert.Equal(t, []*model.Field{
+		{Name: "XMLName", Type: "xml.Name", Tag: `xml:"urn:inventory GetItem"`},
+		{Name: "Sku", Type: "string", Tag: `xml:"sku,omitempty" json:"sku,omitempty"`},
+		{Name: "Warehouse", Type: "int32", Tag: `xml:"warehouse,omitempty" json:"warehouse,omitempty"`},
+	}, operationType(t, getItem, "GetItem").Fields)
+	assert.Equal(t, []*model.Field{
+		{Name: "XMLName", Type: "xml.Name", Tag: `xml:"urn:inventory GetItemResponse"`},
+		{Name: "Item", Type: "*Item", Tag: `xml:"item,omitempty" json:"item,omitempty"`},
+	}, operationType(t, getItem, "GetItemResponse").Fields)
+
+	// operations without an output message have no wrapper for it
+	assert.Equal(t, "Restock", restock.Request)
+	assert.Empty(t, restock.Response)
+	assert.Len(t, restock.Types, 1)
+}
+
+func TestRPCEncodedGeneratesEncodedClient(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/rpc-encoded.wsdl`)
+
+	service := pkg.Services[0]
+	assert.True(t, service.Encoded)
+	assert.False(t, service.UnqualifiedElements)
+	assert.Equal(t, []string{"proxy.WithSOAPEncoding()"}, service.ClientOptions())
+
+	// arrays are slices of their items, which are written with their xsi:type
+	assert.Equal(t, "[]*Line", modelType(t, pkg, "ArrayOfLine").Underlying)
+	assert.Equal(t, "[]string", modelType(t, pkg, "ArrayOfString").Underlying)
+	assert.Nil(t, modelType(t, pkg, "ArrayOfLine").XSIType)
+	assert.Equal(t, &xml.Name{Space: "urn:orders", Local: "Line"}, modelType(t, pkg, "Line").XSIType)
+	assert.Equal(t, &xml.Name{Space: "urn:orders", Local: "Status"}, modelType(t, pkg, "Status").XSIType)
+}
+
+func TestDocumentBareGeneratesBodyElements(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/document-bare.wsdl`)
+
+	getQuote, ping := pkg.Services[0].Operations[0], pkg.Services[0].Operations[1]
+	assert.Equal(t, "GetQuoteRequest", getQuote.Request)
+	assert.Equal(t, "GetQuoteResponse", getQuote.Response)
+
+	// the parts bound to the header are left out of the body
+	request := operationType(t, getQuote, "GetQuoteRequest")
+	assert.Equal(t, []*model.Field{
+		{Name: "Symbol", Type: "string", Tag: `xml:"http://example.com/quotes.xsd symbol,omitempty" json:"symbol,omitempty"`},
+		{Name: "Options", Type: "*Options", Tag: `xml:"http://example.com/quotes.xsd options,omitempty" json:"options,omitempty"`},
+	}, request.Fields)
+	assert.Equal(t, []*model.BodyElement{
+		{Name: xml.Name{Space: "http://example.com/quotes.xsd", Local: "symbol"}, Field: "Symbol"},
+		{Name: xml.Name{Space: "http://example.com/quotes.xsd", Local: "options"}, Field: "Options"},
+	}, request.BodyElements)
+
+	response := operationType(t, getQuote, "GetQuoteResponse")
+	if assert.NotNil(t, response.Field("Timestamp")) {
+		assert.Equal(t, "xsd.DateTime", response.Field("Timestamp").Type)
+	}
+
+	assert.Equal(t, "PingRequest", ping.Request)
+	assert.Empty(t, ping.Response)
+}
+
+func TestWSDLImportsAreMerged(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/wcf/service.wsdl`)
+
+	add := modelType(t, pkg, "Add")
+	if assert.NotNil(t, add.Field("XMLName")) {
+		assert.Equal(t, `xml:"http://example.com/calculator Add"`, add.Field("XMLName").Tag)
+	}
+	assert.NotNil(t, pkg.Type("AddResponse"))
+
+	operation := pkg.Services[0].Operations[0]
+	assert.Equal(t, "Add", operation.Request)
+	assert.Equal(t, "AddResponse", operation.Response)
+	assert.Equal(t, "http://example.com/calculator/ICalculator/Add", operation.Action)
+}
+
+func TestWSDLImportsKeepTheirOwnPrefixes(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/wcf-prefixes/service.wsdl`)
+
+	assert.Equal(t, "string", modelType(t, pkg, "Add").Underlying)
+
+	add := modelType(t, pkg, "Add2")
+	if assert.NotNil(t, add.Field("XMLName")) {
+		assert.Equal(t, `xml:"http://example.com/calculator Add"`, add.Field("XMLName").Tag)
+	}
+
+	operation := pkg.Services[0].Operations[0]
+	assert.Equal(t, "Add2", operation.Request)
+	assert.Equal(t, "AddResponse", operation.Response)
+}
+
+func TestWSDL20GeneratesMessageExchangePatterns(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/wsdl20.wsdl`)
+
+	if assert.NotNil(t, pkg.Type("GetQuote")) && assert.NotNil(t, pkg.Type("GetQuote").Field("XMLName")) {
+		assert.Equal(t, `xml:"http://example.com/quotes.xsd GetQuote"`, pkg.Type("GetQuote").Field("XMLName").Tag)
+	}
+
+	service := pkg.Services[1]
+	assert.Equal(t, "QuotesInterface", service.Name)
+	assert.Equal(t, []string{"proxy.WithSOAP12()"}, service.ClientOptions())
+
+	// out-only operations are left out, the operations of the interfaces extended are inherited
+	if assert.Len(t, service.Operations, 4) {
+		getQuote, getCachedQuote, subscribe, logging := service.Operations[0], service.Operations[1], service.Operations[2], service.Operations[3]
+
+		assert.Equal(t, "GetQuote", getQuote.Name)
+		assert.Equal(t, "urn:GetQuote", getQuote.Action)
+		assert.Equal(t, "GetQuoteResponse", getQuote.Response)
+		assert.False(t, getQuote.OptionalResponse)
+
+		// in-optional-out operations return a nil response when the service replies with nothing
+		assert.Equal(t, "GetCachedQuote", getCachedQuote.Name)
+		assert.Equal(t, "GetQuote", getCachedQuote.Request)
+		assert.Equal(t, "GetQuoteResponse", getCachedQuote.Response)
+		assert.True(t, getCachedQuote.OptionalResponse)
+
+		// robust-in-only operations have faults but no response, in-only ones have neither
+		assert.Equal(t, "Subscribe", subscribe.Name)
+		assert.Empty(t, subscribe.Response)
+		assert.Equal(t, []*model.Fault{{Name: "InvalidSymbolFault"}}, subscribe.Faults)
+
+		assert.Equal(t, "Log", logging.Name)
+		assert.Equal(t, "urn:Log", logging.Action)
+		assert.Empty(t, logging.Response)
+		assert.Empty(t, logging.Faults)
+	}
+}
+
+func TestGroupsAndAttributeGroupsAreExpanded(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/groups.wsdl`)
+
+	// the elements of groups keep the form of the schema declaring them
+	assert.Equal(t, []*model.Field{
+		{Name: "Name", Type: "string", Tag: `xml:"http://example.com/booking.xsd Name" json:"Name"`},
+		{Name: "Email", Type: "string", Tag: `xml:"Email" json:"Email"`},
+		{Name: "Phone", Type: "string", Tag: `xml:"Phone" json:"Phone"`},
+		{Name: "Amount", Type: "float64", Tag: `xml:"Amount,attr,omitempty" json:"Amount,omitempty"`},
+		{Name: "CurrencyCode", Type: "string", Tag: `xml:"CurrencyCode,attr,omitempty" json:"CurrencyCode,omitempty"`},
+		{Name: "DecimalPlaces", Type: "int32", Tag: `xml:"DecimalPlaces,attr,omitempty" json:"DecimalPlaces,omitempty"`},
+	}, modelType(t, pkg, "Customer").Fields)
+	assert.Equal(t, []*model.Field{
+		{Type: "*Customer", Embedded: true},
+		{Name: "Card", Type: "*string", Tag: `xml:"http://example.com/booking.xsd Card,omitempty" json:"Card,omitempty"`},
+		{Name: "Voucher", Type: "*string", Tag: `xml:"http://example.com/booking.xsd Voucher,omitempty" json:"Voucher,omitempty"`},
+		{Name: "CurrencyCode", Type: "string", Tag: `xml:"CurrencyCode,attr,omitempty" json:"CurrencyCode,omitempty"`},
+		{Name: "DecimalPlaces", Type: "int32", Tag: `xml:"DecimalPlaces,attr,omitempty" json:"DecimalPlaces,omitempty"`},
+	}, modelType(t, pkg, "Booking").Fields)
+}
+
+func TestRestrictionsInheritFromBaseType(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/restrictions.wsdl`)
+
+	assert.Equal(t, []*model.Field{
+		{Name: "Code", Type: "string", Tag: `xml:"http://example.com/catalog.xsd Code" json:"Code"`},
+		{Name: "Name", Type: "string", Tag: `xml:"http://example.com/catalog.xsd Name" json:"Name"`, Facets: &model.Facets{MaxLength: "40"}},
+		{Name: "Id", Type: "int32", Tag: `xml:"Id,attr,omitempty" json:"Id,omitempty"`},
+		{Name: "Currency", Type: "string", Tag: `xml:"Currency,attr,omitempty" json:"Currency,omitempty"`, Facets: &model.Facets{Length: "3"}},
+	}, modelType(t, pkg, "CatalogProduct").Fields)
+	assert.Equal(t, []*model.Field{
+		{Name: "Label", Type: "string", Tag: `xml:"Label,attr,omitempty" json:"Label,omitempty"`},
+	}, modelType(t, pkg, "Marker").Fields)
+	assert.Equal(t, []*model.Field{
+		{Name: "Value", Type: "string", Tag: `xml:",chardata" json:"-,"`, Facets: &model.Facets{Enumeration: []string{"GB", "US"}}},
+		{Name: "ListID", Type: "string", Tag: `xml:"ListID,attr,omitempty" json:"ListID,omitempty"`},
+	}, modelType(t, pkg, "CountryCode").Fields)
+
+	// restricted enumerations keep their constants, facets add up to the ones of the base type
+	homeCountryCode := modelType(t, pkg, "HomeCountryCode")
+	assert.Contains(t, homeCountryCode.Constants, &model.Constant{Name: "HomeCountryCodeGB", Type: "string", Value: "GB"})
+	if assert.NotNil(t, homeCountryCode.Field("Value")) {
+		assert.Equal(t, &model.Facets{MaxLength: "2", Enumeration: []string{"GB", "US"}}, homeCountryCode.Field("Value").Facets)
+	}
+}
+
+func TestNestedModelGroupsKeepEveryElementInDocumentOrder(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/particles.wsdl`)
+
+	assert.Equal(t, []*model.Field{
+		{Name: "Id", Type: "string", Tag: `xml:"http://example.com/orders.xsd Id" json:"Id"`},
+		{Name: "FirstName", Type: "*string", Tag: `xml:"http://example.com/orders.xsd FirstName,omitempty" json:"FirstName,omitempty"`},
+		{Name: "LastName", Type: "*string", Tag: `xml:"http://example.com/orders.xsd LastName,omitempty" json:"LastName,omitempty"`},
+		{Name: "CompanyName", Type: "*string", Tag: `xml:"http://example.com/orders.xsd CompanyName,omitempty" json:"CompanyName,omitempty"`},
+		{Name: "Street", Type: "string", Tag: `xml:"http://example.com/orders.xsd Street" json:"Street"`},
+		{Name: "City", Type: "string", Tag: `xml:"http://example.com/orders.xsd City" json:"City"`},
+		{Name: "Email", Type: "string", Tag: `xml:"http://example.com/orders.xsd Email" json:"Email"`},
+	}, modelType(t, pkg, "Party").Fields)
+	assert.Equal(t, []*model.Field{
+		{Type: "*Party", Embedded: true},
+		{Name: "Phone", Type: "*string", Tag: `xml:"http://example.com/orders.xsd Phone,omitempty" json:"Phone,omitempty"`},
+		{Name: "Fax", Type: "*string", Tag: `xml:"http://example.com/orders.xsd Fax,omitempty" json:"Fax,omitempty"`},
+		{Name: "Rating", Type: "int32", Tag: `xml:"http://example.com/orders.xsd Rating" json:"Rating"`},
+	}, modelType(t, pkg, "Customer").Fields)
+
+	// encoding/xml hands the elements of several repeated groups to a single field, holding them in document order
+	assert.Equal(t, []*model.Field{
+		{Name: "Number", Type: "string", Tag: `xml:"http://example.com/orders.xsd Number" json:"Number"`},
+		{Name: "Content", Type: "*OrderContent", Tag: `xml:",any" json:"content,omitempty"`},
+	}, modelType(t, pkg, "Order").Fields)
+
+	content := modelType(t, pkg, "OrderContent")
+	assert.True(t, content.GroupContent)
+	assert.Equal(t, []*model.Field{
+		{Name: "Choice", Type: "OrderChoiceGroups", Tag: `xml:",any" json:"choice,omitempty"`},
+		{Name: "Note", Type: "[]string", Tag: `xml:"http://example.com/orders.xsd Note" json:"Note"`},
+		{Name: "Sequence", Type: "OrderSequenceGroups", Tag: `xml:",any" json:"sequence,omitempty"`},
+	}, content.Fields)
+
+	assert.Equal(t, []*model.Field{
+		{Name: "Street", Type: "string", Tag: `xml:"http://example.com/orders.xsd Street" json:"Street"`},
+		{Name: "City", Type: "string", Tag: `xml:"http://example.com/orders.xsd City" json:"City"`},
+	}, modelType(t, pkg, "OrderSequenceGroup").Fields)
+	assert.Equal(t, []*model.Field{
+		{Name: "Product", Type: "*string", Tag: `xml:"http://example.com/orders.xsd Product,omitempty" json:"Product,omitempty"`},
+		{Name: "Service", Type: "*string", Tag: `xml:"http://example.com/orders.xsd Service,omitempty" json:"Service,omitempty"`},
+		{Name: "Fee", Type: "*float64", Tag: `xml:"http://example.com/orders.xsd Fee,omitempty" json:"Fee,omitempty"`},
+	}, modelType(t, pkg, "OrderChoiceGroup").Fields)
+
+	choices := modelType(t, pkg, "OrderChoiceGroups")
+	assert.True(t, choices.ModelGroup)
+	assert.Equal(t, "[]*OrderChoiceGroup", choices.Underlying)
+}
+
+func TestOccurrencesShapeElementFields(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/occurrences.wsdl`)
+
+	// repeated elements are slices, optional ones pointers, nillable ones xsd.Nillable, required ones are written
+	// even when empty
+	assert.Equal(t, []*model.Field{
+		{Name: "Question", Type: "string", Tag: `xml:"http://example.com/survey.xsd Question" json:"Question"`},
+		{Name: "Score", Type: "*int32", Tag: `xml:"http://example.com/survey.xsd Score,omitempty" json:"Score,omitempty"`},
+		{Name: "Choices", Type: "[]string", Tag: `xml:"http://example.com/survey.xsd Choices" json:"Choices"`, Occurs: &model.Occurs{Min: 1, Max: 5}},
+		{Name: "Level", Type: "*Level", Tag: `xml:"http://example.com/survey.xsd Level,omitempty" json:"Level,omitempty"`},
+		{Name: "Comment", Type: "xsd.Nillable[string]", Tag: `xml:"http://example.com/survey.xsd Comment" json:"Comment"`, Required: true},
+		{Name: "Attachment", Type: "[]byte", Tag: `xml:"http://example.com/survey.xsd Attachment,omitempty" json:"Attachment,omitempty"`},
+		{Name: "ReviewedBy", Type: "*string", Tag: `xml:"http://example.com/survey.xsd ReviewedBy,omitempty" json:"ReviewedBy,omitempty"`},
+		{Name: "Approved", Type: "*bool", Tag: `xml:"http://example.com/survey.xsd Approved,omitempty" json:"Approved,omitempty"`},
+	}, modelType(t, pkg, "Answer").Fields)
+}
+
+func TestListTypesWriteTheirItemsSeparatedBySpaces(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/lists.wsdl`)
+
+	weekdays := modelType(t, pkg, "Weekdays")
+	assert.Equal(t, "[]Weekday", weekdays.Underlying)
+	assert.Equal(t, "Weekday", weekdays.ListItem)
+	assert.Equal(t, "xsd.Date", modelType(t, pkg, "Dates").ListItem)
+
+	// types declared from a list type keep writing their items
+	for _, name := range []string{"WorkingDays", "Workdays"} {
+		assert.Equal(t, "Weekdays", modelType(t, pkg, name).Underlying)
+		assert.Equal(t, "Weekday", modelType(t, pkg, name).ListItem)
+	}
+	assert.Equal(t, &model.Facets{MaxLength: "5"}, modelType(t, pkg, "WorkingDays").Facets)
+
+	// anonymous lists are held by xsd.ListOf
+	assert.Equal(t, []*model.Field{
+		{Name: "Days", Type: "*Weekdays", Tag: `xml:"Days" json:"Days"`, Required: true},
+		{Name: "Holidays", Type: "*Dates", Tag: `xml:"Holidays,omitempty" json:"Holidays,omitempty"`},
+		{Name: "Slots", Type: "xsd.ListOf[int32]", Tag: `xml:"Slots" json:"Slots"`},
+		{Name: "Closed", Type: "*Weekdays", Tag: `xml:"closed,attr,omitempty" json:"closed,omitempty"`},
+		{Name: "Ratios", Type: "xsd.ListOf[float64]", Tag: `xml:"ratios,attr,omitempty" json:"ratios,omitempty"`},
+	}, modelType(t, pkg, "Schedule").Fields)
+}
+
+func TestUnionTypesHoldAValueOfOneOfTheirMemberTypes(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/unions.wsdl`)
+
+	// members are tried in the order they are declared, anonymous ones included along with their enumerations
+	size := modelType(t, pkg, "Size")
+	assert.True(t, size.IsStruct())
+	assert.Equal(t, []*model.UnionMember{
+		{Name: "Int_", Type: "int32", Constructor: "NewSizeInt_"},
+		{Name: "SizeLabel", Type: "SizeLabel", Constructor: "NewSizeSizeLabel", Enumeration: []string{"small", "large"}},
+		{Name: "Member3", Type: "string", Constructor: "NewSizeMember3", Enumeration: []string{"unknown"}},
+		{Name: "Member4", Type: "xsd.Date", Constructor: "NewSizeMember4"},
+	}, size.Union)
+
+	// types declared from a union are unions too
+	for _, name := range []string{"ShoeSize", "DefaultSize"} {
+		if assert.Len(t, modelType(t, pkg, name).Union, 4) {
+			assert.Equal(t, "New"+name+"SizeLabel", modelType(t, pkg, name).Union[1].Constructor)
+		}
+	}
+
+	assert.Equal(t, []*model.Field{
+		{Name: "Size", Type: "*Size", Tag: `xml:"Size" json:"Size"`, Required: true},
+		{Name: "ShoeSize", Type: "*ShoeSize", Tag: `xml:"ShoeSize,omitempty" json:"ShoeSize,omitempty"`},
+		{Name: "Fit", Type: "*Size", Tag: `xml:"fit,attr,omitempty" json:"fit,omitempty"`},
+	}, modelType(t, pkg, "Garment").Fields)
+}
+
+func TestTypesValidateTheConstraintsOfTheirSchema(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/occurrences.wsdl`)
+
+	level := modelType(t, pkg, "Level")
+	assert.True(t, level.CanValidate())
+	assert.Equal(t, &model.Facets{Enumeration: []string{"Low", "High"}}, level.Facets)
+
+	answer := modelType(t, pkg, "Answer")
+	if assert.NotNil(t, answer.Field("Choices")) && assert.NotNil(t, answer.Field("Level")) && assert.NotNil(t, answer.Field("Comment")) {
+		assert.Equal(t, &model.Occurs{Min: 1, Max: 5}, answer.Field("Choices").Occurs)
+		assert.True(t, answer.Field("Level").HoldsValidated())
+		assert.True(t, answer.Field("Comment").Required)
+		assert.False(t, answer.Field("Comment").HoldsValidated())
+	}
+
+	logs := new(bytes.Buffer)
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+
+	pkg = buildModel(t, `wsdl-samples/restrictions.wsdl`)
+
+	// patterns RE2 can't compile are warned about
+	assert.Contains(t, logs.String(), `[WARN] Pattern "\\i\\c*" of Sku can't be compiled`)
+	assert.Equal(t, &model.Facets{Pattern: `\i\c*`}, modelType(t, pkg, "Sku").Facets)
+
+	// inline simple types are checked on the fields holding them, base types through the embedded field
+	product := modelType(t, pkg, "CatalogProduct")
+	if assert.NotNil(t, product.Field("Name")) && assert.NotNil(t, product.Field("Currency")) {
+		assert.Equal(t, &model.Facets{MaxLength: "40"}, product.Field("Name").Facets)
+		assert.Equal(t, &model.Facets{Length: "3"}, product.Field("Currency").Facets)
+	}
+	assert.True(t, modelType(t, pkg, "Product").Fields[0].HoldsValidated())
+	assert.True(t, modelType(t, pkg, "Item").Polymorphic)
+
+	listing := modelType(t, pkg, "Listing")
+	if assert.NotNil(t, listing.Field("Product")) {
+		assert.True(t, listing.Field("Product").Required)
+		assert.True(t, listing.Field("Product").HoldsValidated())
+	}
+}
+
+func TestDefaultAndFixedValuesArePreset(t *testing.T) {
+	logs := new(bytes.Buffer)
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+
+	pkg := buildModel(t, `wsdl-samples/defaults.wsdl`)
+
+	// values the constructor can't set are warned about
+	assert.Contains(t, logs.String(), `[WARN] Default value "none" of Invoice.Discount is not a valid float64`)
+	assert.Equal(t, 1, strings.Count(logs.String(), "is not a valid"))
+
+	// defaults of referenced elements are the ones of their declaration, repeated elements are not preset
+	invoice := modelType(t, pkg, "Invoice")
+	assert.Equal(t, "NewInvoice", invoice.Constructor)
+	assert.Equal(t, []*model.FieldPreset{
+		{Path: "Amount", Preset: &model.Preset{Constant: "InvoiceAmountDefault", Value: "0.5"}},
+		{Path: "Currency", Preset: &model.Preset{Constant: "InvoiceCurrencyDefault", Value: "EUR"}},
+		{Path: "Paid", Preset: &model.Preset{Constant: "InvoicePaidDefault", Value: "false"}},
+		{Path: "Copies", Preset: &model.Preset{Constant: "InvoiceCopiesFixed", Value: "1", Fixed: true}},
+		{Path: "Version", Preset: &model.Preset{Constant: "InvoiceVersionFixed", Value: "1.0", Fixed: true}},
+		{Path: "Priority", Preset: &model.Preset{Constant: "InvoicePriorityDefault", Value: "3"}},
+		{Path: "Discount", Preset: &model.Preset{Constant: "InvoiceDiscountDefault", Value: "none"}},
+		{Path: "SchemaVersion", Preset: &model.Preset{Constant: "InvoiceSchemaVersionFixed", Value: "2.1", Fixed: true}},
+	}, invoice.Presets())
+	assert.Contains(t, invoice.Constants, &model.Constant{Name: "InvoiceAmountDefault", Value: "0.5",
+		Doc: "InvoiceAmountDefault is the default value of Invoice.Amount."})
+
+	// fixed attributes are written even when left empty
+	if assert.NotNil(t, invoice.Field("Version")) && assert.NotNil(t, invoice.Field("Priority")) {
+		assert.Equal(t, "InvoiceVersion", invoice.Field("Version").Type)
+		assert.Equal(t, `xml:"version,attr" json:"version,omitempty"`, invoice.Field("Version").Tag)
+		assert.Equal(t, `xml:"priority,attr,omitempty" json:"priority,omitempty"`, invoice.Field("Priority").Tag)
+	}
+	assert.Equal(t, "InvoiceVersionFixed", modelType(t, pkg, "InvoiceVersion").FixedValue)
+}
+
+func TestMixedContentKeepsTextAndElementsInOrder(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/mixed.wsdl`)
+
+	paragraph := modelType(t, pkg, "Paragraph")
+	assert.True(t, paragraph.Mixed)
+	assert.Equal(t, []*model.Field{
+		{Name: "Lang", Type: "string", Tag: `xml:"lang,attr,omitempty" json:"lang,omitempty"`},
+		{Name: "Content", Type: "[]xsd.MixedNode", Tag: `xml:",any" json:"content,omitempty"`,
+			Doc: "Content holds the text and the child elements of Paragraph in document order."},
+	}, paragraph.Fields)
+
+	elements := []*model.MixedElement{
+		{Accessor: "B", Name: xml.Name{Space: "http://example.com/notes.xsd", Local: "b"}, Type: "string"},
+		{Accessor: "I", Name: xml.Name{Space: "http://example.com/notes.xsd", Local: "i"}, Type: "string"},
+		{Accessor: "Link", Name: xml.Name{Space: "http://example.com/notes.xsd", Local: "link"}, Type: "Link"},
+	}
+	assert.Equal(t, elements, paragraph.MixedElements)
+
+	// derived types read the elements of their base type, whose content they hold
+	note := modelType(t, pkg, "Note")
+	assert.True(t, note.Mixed)
+	assert.Equal(t, []*model.Field{
+		{Type: "*Paragraph", Embedded: true},
+		{Name: "Author", Type: "string", Tag: `xml:"author,attr,omitempty" json:"author,omitempty"`},
+	}, note.Fields)
+	assert.Equal(t, append(elements, &model.MixedElement{Accessor: "Signature",
+		Name: xml.Name{Space: "http://example.com/notes.xsd", Local: "signature"}, Type: "string"}), note.MixedElements)
+
+	// the mixed attribute of a complex content overrides the one of its type
+	assert.False(t, modelType(t, pkg, "Leave").Mixed)
+	if assert.NotNil(t, modelType(t, pkg, "Period").Field("From")) {
+		assert.Equal(t, "xsd.Date", modelType(t, pkg, "Period").Field("From").Type)
+	}
+}
+
+func TestWildcardsKeepUnknownContent(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/wildcards.wsdl`)
+
+	// the attribute wildcard of an attribute group is the one of the types referencing it, and is promoted to
+	// derived types
+	assert.Equal(t, []*model.Field{
+		{Name: "Id", Type: "int32", Tag: `xml:"Id" json:"Id"`},
+		{Name: "Items", Type: "[]*xsd.Node", Tag: `xml:",any" json:"items,omitempty"`},
+		{Name: "AnyAttrs", Type: "[]xml.Attr", Tag: `xml:",any,attr" json:"anyAttrs,omitempty"`},
+	}, modelType(t, pkg, "Record").Fields)
+	assert.Equal(t, []*model.Field{
+		{Type: "*Record", Embedded: true},
+		{Name: "Tag", Type: "string", Tag: `xml:"tag,attr,omitempty" json:"tag,omitempty"`},
+	}, modelType(t, pkg, "TaggedRecord").Fields)
+	assert.True(t, pkg.HasWildcards())
+
+	assert.Contains(t, pkg.GlobalElements, &model.Registration{
+		Name: xml.Name{Space: "http://example.com/extensions.xsd", Local: "Location"}, GoType: "Location"})
+}
+
+func TestFormsQualifyElementsAndAttributes(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/qualification.wsdl`)
+
+	// local declarations follow their form or the schema defaults, global ones are always qualified
+	assert.Equal(t, []*model.Field{
+		{Name: "Reference", Type: "string", Tag: `xml:"Reference" json:"Reference"`},
+		{Name: "Carrier", Type: "string", Tag: `xml:"http://example.com/shipping.xsd Carrier" json:"Carrier"`},
+		{Name: "Weight", Type: "*Weight", Tag: `xml:"http://example.com/units.xsd Weight" json:"Weight"`, Required: true},
+		{Name: "Note", Type: "*string", Tag: `xml:"Note,omitempty" json:"Note,omitempty"`, Facets: &model.Facets{MaxLength: "80"}},
+		{Name: "Id", Type: "string", Tag: `xml:"id,attr,omitempty" json:"id,omitempty"`},
+		{Name: "Priority", Type: "int32", Tag: `xml:"http://example.com/shipping.xsd priority,attr,omitempty" json:"priority,omitempty"`},
+		{Name: "Unit", Type: "string", Tag: `xml:"http://example.com/units.xsd unit,attr,omitempty" json:"unit,omitempty"`},
+		{Name: "Lang", Type: "string", Tag: `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty" json:"lang,omitempty"`},
+	}, modelType(t, pkg, "Parcel").Fields)
+
+	// the client takes unqualified elements out of the namespace of their parent, which qualified ones don't need
+	assert.True(t, pkg.Services[0].UnqualifiedElements)
+	assert.Equal(t, []string{"proxy.WithUnqualifiedElements()"}, pkg.Services[0].ClientOptions())
+
+	pkg = buildModel(t, `wsdl-samples/restrictions.wsdl`)
+	assert.False(t, pkg.Services[0].UnqualifiedElements)
+	assert.Empty(t, pkg.Services[0].ClientOptions())
+}
+
+func TestDerivedTypesArePolymorphic(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/polymorphism.wsdl`)
+
+	draw := modelType(t, pkg, "Draw")
+	if assert.NotNil(t, draw.Field("Shape")) && assert.NotNil(t, draw.Field("Frame")) {
+		assert.Equal(t, "[]*AnyShape", draw.Field("Shape").Type)
+		assert.Equal(t, `xml:"http://example.com/drawing.xsd Shape" json:"Shape"`, draw.Field("Shape").Tag)
+		assert.Equal(t, "*AnyRectangle", draw.Field("Frame").Type)
+	}
+
+	// types with derived types are held by an interface their derived types implement
+	assert.True(t, modelType(t, pkg, "Shape").Polymorphic)
+	assert.True(t, modelType(t, pkg, "Rectangle").Polymorphic)
+	assert.False(t, modelType(t, pkg, "Square").Polymorphic)
+	assert.Equal(t, []string{"isRectangle", "isShape"}, modelType(t, pkg, "Square").Markers)
+	assert.Equal(t, []string{"isShape"}, modelType(t, pkg, "Circle").Markers)
+	assert.Contains(t, pkg.XSITypes, &model.Registration{
+		Name: xml.Name{Space: "http://example.com/drawing.xsd", Local: "Square"}, GoType: "Square"})
+}
+
+func TestSubstitutionGroupsAreChoices(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/substitution-groups.wsdl`)
+
+	assert.Equal(t, []*model.Field{
+		{Name: "XMLName", Type: "xml.Name", Tag: `xml:"http://example.com/properties.xsd GetProperties"`},
+		{Name: "Path", Type: "[]*PathChoice", Tag: `xml:",any" json:"Path,omitempty"`, Occurs: &model.Occurs{Min: 1, Max: -1}},
+	}, modelType(t, pkg, "GetProperties").Fields)
+	assert.True(t, modelType(t, pkg, "Path").SubstitutionGroupHead)
+	assert.Equal(t, []string{"isFieldURIMember", "isPathMember"}, modelType(t, pkg, "IndexedFieldURI").Markers)
+
+	// abstract heads are not members of their own group
+	assert.Equal(t, []*model.Registration{
+		{Name: xml.Name{Space: "http://example.com/properties.xsd", Local: "FieldURI"}, GoType: "FieldURI"},
+		{Name: xml.Name{Space: "http://example.com/properties.xsd", Local: "IndexedFieldURI"}, GoType: "IndexedFieldURI"},
+	}, pkg.SubstitutionGroupMembers)
+}
+
+func TestSameNamedTypesInDifferentNamespacesAreDisambiguated(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/namespaces.wsdl`)
+
+	assert.Equal(t, "ResponseType", modelType(t, pkg, "OrderResponse").Underlying)
+	assert.Equal(t, "ResponseType2", modelType(t, pkg, "InvoiceResponse").Underlying)
+	assert.Equal(t, []*model.Field{
+		{Name: "InvoiceNumber", Type: "int32", Tag: `xml:"http://example.com/invoices.xsd InvoiceNumber" json:"InvoiceNumber"`},
+		{Name: "Order", Type: "*ResponseType", Tag: `xml:"http://example.com/invoices.xsd Order" json:"Order"`, Required: true},
+	}, modelType(t, pkg, "ResponseType2").Fields)
+
+	operation := pkg.Services[0].Operations[0]
+	assert.Equal(t, "ResponseType", operation.Request)
+	assert.Equal(t, "ResponseType2", operation.Response)
+}
+
+func TestNameCollisionsAreNumbered(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/naming.wsdl`)
+
+	assert.NotNil(t, pkg.Type("Order_item"))
+	assert.NotNil(t, pkg.Type("Order_item2"))
+	if assert.NotNil(t, pkg.Type("Order2")) && assert.NotNil(t, pkg.Type("Order2").Field("XMLName")) {
+		assert.Equal(t, `xml:"http://example.com/orders.xsd Order"`, pkg.Type("Order2").Field("XMLName").Tag)
+	}
+
+	// the names derived from a polymorphic type are claimed along with its own
+	assert.False(t, modelType(t, pkg, "AnyShape").Polymorphic)
+	assert.True(t, modelType(t, pkg, "Shape2").Polymorphic)
+
+	// fields are numbered after the fields and methods of their struct
+	assert.Equal(t, []*model.Field{
+		{Name: "Id", Type: "string", Tag: `xml:"http://example.com/orders.xsd id" json:"id"`},
+		{Name: "Validate2", Type: "bool", Tag: `xml:"http://example.com/orders.xsd validate" json:"validate"`},
+		{Name: "Status", Type: "*Order_status", Tag: `xml:"http://example.com/orders.xsd status" json:"status"`, Required: true},
+		{Name: "Item", Type: "[]*Order_item", Tag: `xml:"http://example.com/orders.xsd item" json:"item"`, Occurs: &model.Occurs{Min: 1, Max: -1}},
+		{Name: "Shape", Type: "*AnyShape2", Tag: `xml:"http://example.com/orders.xsd shape,omitempty" json:"shape,omitempty"`},
+		{Name: "Id2", Type: "string", Tag: `xml:"id,attr,omitempty" json:"id2,omitempty"`},
+	}, modelType(t, pkg, "Order").Fields)
+
+	// fields named after basic types are exported all the same
+	assert.Equal(t, []*model.Field{
+		{Name: "String_", Type: "[]string", Tag: `xml:"http://example.com/orders.xsd string" json:"string"`, Occurs: &model.Occurs{Min: 1, Max: -1}},
+		{Name: "Int_", Type: "*int32", Tag: `xml:"http://example.com/orders.xsd int,omitempty" json:"int,omitempty"`},
+		{Name: "This", Type: "string", Tag: `xml:"http://example.com/orders.xsd _this" json:"_this"`},
+		{Name: "X1st", Type: "*string", Tag: `xml:"http://example.com/orders.xsd 1st,omitempty" json:"1st,omitempty"`},
+	}, modelType(t, pkg, "Tags").Fields)
+
+	operations := pkg.Services[0].Operations
+	if assert.Len(t, operations, 2) {
+		assert.Equal(t, "Order", operations[0].Name)
+		assert.Equal(t, "Order2", operations[1].Name)
+		for _, operation := range operations {
+			assert.Equal(t, "Order2", operation.Request)
+			assert.Equal(t, "OrderResponse", operation.Response)
+		}
+	}
+}
+
+func TestCamelCaseNaming(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/naming.wsdl`, builder.WithCamelCase("SKU"))
+
+	assert.Equal(t, []*model.Field{
+		{Name: "ProductID", Type: "string", Tag: `xml:"http://example.com/orders.xsd product_id" json:"product_id"`},
+		{Name: "ImageURL", Type: "*AnyURI", Tag: `xml:"http://example.com/orders.xsd image-url,omitempty" json:"image-url,omitempty"`},
+	}, modelType(t, pkg, "OrderItem").Fields)
+	assert.NotNil(t, modelType(t, pkg, "OrderItem2").Field("SKU"))
+	assert.NotNil(t, modelType(t, pkg, "OrderItem3").Field("Quantity"))
+
+	if assert.NotNil(t, modelType(t, pkg, "OrderResponse").Field("TrackingURL")) {
+		assert.Equal(t, "AnyURI", modelType(t, pkg, "OrderResponse").Field("TrackingURL").Type)
+	}
+	assert.NotNil(t, modelType(t, pkg, "Order").Field("ID2"))
+
+	tags := modelType(t, pkg, "Tags")
+	for _, name := range []string{"String", "Int", "This", "X1st"} {
+		assert.NotNil(t, tags.Field(name), name)
+	}
+
+	assert.Equal(t, []*model.Constant{
+		{Name: "OrderStatusInProgress", Type: "OrderStatus", Value: "in-progress"},
+		{Name: "OrderStatusInProgress2", Type: "OrderStatus", Value: "in_progress"},
+		{Name: "OrderStatusDone", Type: "OrderStatus", Value: "done"},
+	}, modelType(t, pkg, "OrderStatus").Constants)
+}
+
+func TestBindingsCustomizeTheGeneratedCode(t *testing.T) {
+	pkg := buildModel(t, `wsdl-samples/bindings.wsdl`, builder.WithBindings(`wsdl-samples/bindings.xml`))
+
+	assert.Equal(t, []*model.Import{
+		{Alias: "big", Path: "math/big", Type: "*big.Float"},
+		{Alias: "time", Path: "time", Type: "time.Time"},
+	}, pkg.Imports)
+
+	// renamed types and fields, tags added to the ones of the schema, types mapped to Go types are not generated
+	assert.Equal(t, []*model.Field{
+		{Name: "SKU", Type: "string", Tag: `xml:"http://example.com/orders.xsd sku" json:"sku" db:"sku"`},
+		{Name: "Quantity", Type: "int32", Tag: `xml:"http://example.com/orders.xsd quantity" json:"quantity"`},
+		{Name: "Price", Type: "*big.Float", Tag: `xml:"http://example.com/orders.xsd price" json:"price"`, Required: true},
+	}, modelType(t, pkg, "LineItem").Fields)
+
+	// skipped types are left out along with the fields holding them
+	assert.Equal(t, []*model.Field{
+		{Name: "XMLName", Type: "xml.Name", Tag: `xml:"http://example.com/orders.xsd Order"`},
+		{Name: "Item", Type: "[]*LineItem", Tag: `xml:"http://example.com/orders.xsd item" json:"item"`, Occurs: &model.Occurs{Min: 1, Max: -1}},
+		{Name: "Placed", Type: "time.Time", Tag: `xml:"http://example.com/orders.xsd placed" json:"placed"`},
+		{Name: "Reference", Type: "string", Tag: `xml:"id,attr,omitempty" json:"id,omitempty"`},
+	}, modelType(t, pkg, "Order").Fields)
+	assert.Nil(t, pkg.Type("Timestamp"))
+	assert.Nil(t, pkg.Type("Legacy"))
+
+	operation := pkg.Services[0].Operations[0]
+	assert.Equal(t, "Place", operation.Name)
+	assert.Equal(t, "Order", operation.Request)
+	assert.Equal(t, "Receipt", operation.Response)
+}
+
+func TestBindingsSelectingNothingFail(t *testing.T) {
+	g, err := gowsdlsoap.New(`wsdl-samples/bindings.wsdl`, "soapApi", false, true, builder.WithBindings(`wsdl-samples/bindings-unmatched.xml`))
+	assert.NoError(t, err)
+
+	_, err = g.Model()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "o:Invoice: selects nothing")
+		assert.Contains(t, err.Error(), "tns:Orders/CancelOrder: selects nothing")
+	}
+}
+
+func TestEpisodesImportTheTypesOfOtherPackages(t *testing.T) {
+	g, err := gowsdlsoap.New(`wsdl-samples/episode-common.wsdl`, "common", false, true, builder.WithEpisode("example.com/soap/common"))
+	assert.NoError(t, err)
+
+	resp, err := g.Build()
+	assert.NoError(t, err)
+
+	episode := string(resp["episode"])
+	assert.Contains(t, episode, `<episode package="common" import="example.com/soap/common">`)
+	assert.Contains(t, episode, `<type name="Shape" goType="Shape" polymorphic="true">`)
+	assert.Contains(t, episode, `<element name="Address" goType="Address">`)
+
+	file := filepath.Join(t.TempDir(), "common.episode")
+	assert.NoError(t, ioutil.WriteFile(file, resp["episode"], 0644))
+
+	pkg := buildModel(t, `wsdl-samples/episode-shipping.wsdl`, builder.WithEpisodes(file))
+
+	assert.Equal(t, []*model.Import{{Alias: "common", Path: "example.com/soap/common", Type: "common.Address"}}, pkg.Imports)
+	assert.Equal(t, []*model.Field{
+		{Name: "XMLName", Type: "xml.Name", Tag: `xml:"http://example.com/shipping.xsd Shipment"`},
+		{Name: "To", Type: "*common.Address", Tag: `xml:"http://example.com/shipping.xsd To" json:"To"`, Required: true},
+		{Name: "Parcel", Type: "*common.AnyShape", Tag: `xml:"http://example.com/shipping.xsd Parcel" json:"Parcel"`, Required: true},
+	}, modelType(t, pkg, "Shipment").Fields)
+	if assert.NotEmpty(t, modelType(t, pkg, "Box").Fields) {
+		assert.Equal(t, &model.Field{Type: "*common.Shape", Embedded: true}, modelType(t, pkg, "Box").Fields[0])
+	}
+	assert.Nil(t, pkg.Type("Address"))
+	assert.Nil(t, pkg.Type("Circle"))
+}
+
+func TestModelResolvesTypesAndOperations(t *testing.T) {
+	g, err := gowsdlsoap.New(`wsdl-samples/namespaces.wsdl`, "soapApi", false, true)
+	assert.NoError(t, err)
+
+	pkg, err := g.Model()
+	assert.NoError(t, err)
+	assert.Equal(t, "soapApi", pkg.Name)
+
+	invoice := pkg.Type("ResponseType2")
+	if assert.NotNil(t, invoice) && assert.NotNil(t, invoice.Field("Order")) {
+		assert.True(t, invoice.IsStruct())
+		assert.Equal(t, "*ResponseType", invoice.Field("Order").Type)
+		assert.Equal(t, `xml:"http://example.com/invoices.xsd Order" json:"Order"`, invoice.Field("Order").Tag)
+	}
+
+	if assert.NotNil(t, pkg.Type("InvoiceResponse")) {
+		assert.Equal(t, "ResponseType2", pkg.Type("InvoiceResponse").Underlying)
+	}
+
+	if assert.Len(t, pkg.Services, 1) && assert.Len(t, pkg.Services[0].Operations, 1) {
+		operation := pkg.Services[0].Operations[0]
+		assert.Equal(t, "BillingPortType", pkg.Services[0].Name)
+		assert.Equal(t, "GetInvoice", operation.Name)
+		assert.Equal(t, "urn:GetInvoice", operation.Action)
+		assert.Equal(t, "ResponseType", operation.Request)
+		assert.Equal(t, "ResponseType2", operation.Response)
+	}
+}
+
+func TestModelResolvesMessagesPortTypesAndBindingsByQualifiedName(t *testing.T) {
+	g, err := gowsdlsoap.New(`wsdl-samples/same-names/service.wsdl`, "soapApi", false, true)
+	assert.NoError(t, err)
+
+	pkg, err := g.Model()
+	assert.NoError(t, err)
+
+	if assert.Len(t, pkg.Services, 2) {
+		billing, shipping := pkg.Services[0], pkg.Services[1]
+		assert.Equal(t, "Service", billing.Name)
+		assert.True(t, billing.SOAP12)
+		assert.Equal(t, "Invoice", billing.Operations[0].Request)
+		assert.Equal(t, "urn:billing:Send", billing.Operations[0].Action)
+
+		assert.Equal(t, "Service2", shipping.Name)
+		assert.False(t, shipping.SOAP12)
+		assert.Equal(t, "Parcel", shipping.Operations[0].Request)
+		assert.Equal(t, "urn:shipping:Send", shipping.Operations[0].Action)
+	}
+}
+
+// buildModel returns the model of the code generated from a sample, failing the test when it can't be built.
+func buildModel(t *testing.T, file string, opt ...builder.Option) *model.Package {
+	t.Helper()
+
+	g, err := gowsdlsoap.New(file, "soapApi", false, true, opt...)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	pkg, err := g.Model()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return pkg
+}
+
+// modelType returns the type of a package named name, failing the test when there is none.
+func modelType(t *testing.T, pkg *model.Package, name string) *model.Type {
+	t.Helper()
+
+	typ := pkg.Type(name)
+	if typ == nil {
+		t.Fatalf("type %s is missing", name)
+	}
+
+	return typ
+}
+
+// operationType returns the type declared by an operation named name, failing the test when there is none.
+func operationType(t *testing.T, operation *model.Operation, name string) *model.Type {
+	t.Helper()
+
+	for _, typ := range operation.Types {
+		if typ.Name == name {
+			return typ
+		}
+	}
+
+	t.Fatalf("type %s of %s is missing", name, operation.Name)
+
+	return nil
+}
+
 func getTypeDeclaration(resp map[string][]byte, name string) (string, error) {
 	source, err := format.Source([]byte(string(resp["header"]) + string(resp["types"])))
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -127,6 +128,1365 @@ func TestClient_Send_Correct_Headers(t *testing.T) {
 	}
 }
 
+func TestClient_SOAP12(t *testing.T) {
+	var gotHeaders http.Header
+	var gotEnvelope struct {
+		XMLName xml.Name
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header
+		_ = xml.NewDecoder(r.Body).Decode(&gotEnvelope)
+
+		w.Header().Set(soap.ContentTypeHeader, soap.Soap12ContentType)
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope">
+				<env:Body>
+					<PingResponse xmlns="http://example.com/service.xsd">
+						<PingResult><Message>Pong</Message></PingResult>
+					</PingResponse>
+				</env:Body>
+			</env:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL, proxy.WithSOAP12())
+	reply := &PingResponse{}
+	err := client.Call("GetData", &Ping{Request: &PingRequest{Message: "Ping"}}, reply)
+	assert.NoError(t, err)
+
+	assert.Equal(t, `application/soap+xml; charset="utf-8"; action="GetData"`, gotHeaders.Get(soap.ContentTypeHeader))
+	assert.Empty(t, gotHeaders.Get("SOAPAction"))
+	assert.Equal(t, soap.XmlNsSoap12Env, gotEnvelope.XMLName.Space)
+	assert.Equal(t, "Pong", reply.PingResult.Message)
+}
+
+func TestClient_SOAP12Fault(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope" xmlns:m="http://example.com/faults">
+				<env:Body>
+					<env:Fault>
+						<env:Code>
+							<env:Value>env:Sender</env:Value>
+							<env:Subcode>
+								<env:Value>m:MessageTimeout</env:Value>
+								<env:Subcode><env:Value>m:Retry</env:Value></env:Subcode>
+							</env:Subcode>
+						</env:Code>
+						<env:Reason>
+							<env:Text xml:lang="es">Tiempo agotado</env:Text>
+							<env:Text xml:lang="en">Sender Timeout</env:Text>
+						</env:Reason>
+						<env:Node>http://example.com/node</env:Node>
+						<env:Role>http://example.com/role</env:Role>
+						<env:Detail>
+							<SimpleNode><Detail>detail message</Detail><Num>7.7</Num></SimpleNode>
+						</env:Detail>
+					</env:Fault>
+				</env:Body>
+			</env:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL, proxy.WithSOAP12())
+
+	reply := &PingResponse{}
+	err := client.Call("GetData", &Ping{Request: &PingRequest{Message: "Ping"}}, reply)
+	assert.EqualError(t, err, "Sender Timeout")
+
+	fault, ok := err.(*soap.Fault12)
+	if assert.True(t, ok) {
+		assert.Equal(t, "env:Sender", fault.Code.Value)
+		assert.Equal(t, []string{"m:MessageTimeout", "m:Retry"}, fault.Code.Subcodes())
+		assert.Equal(t, "Tiempo agotado", fault.ReasonText("es"))
+		assert.Equal(t, "http://example.com/node", fault.Node)
+		assert.Equal(t, "http://example.com/role", fault.Role)
+	}
+
+	detail := Wrapper{Item: &SimpleNode{}, hasData: true}
+	err = client.CallWithFaultDetail("GetData", &Ping{Request: &PingRequest{Message: "Ping"}}, reply, &detail)
+	assert.EqualError(t, err, "7.70: detail message")
+}
+
+type OrderLine struct {
+	Sku      string `xml:"sku"`
+	Quantity int32  `xml:"quantity"`
+}
+
+func (OrderLine) XSIType() xml.Name {
+	return xml.Name{Space: "urn:orders", Local: "Line"}
+}
+
+type PlaceOrder struct {
+	XMLName  xml.Name     `xml:"urn:orders PlaceOrder"`
+	Customer string       `xml:"customer"`
+	Lines    []*OrderLine `xml:"lines"`
+}
+
+type PlaceOrderResponse struct {
+	XMLName  xml.Name     `xml:"urn:orders PlaceOrderResponse"`
+	Id       string       `xml:"order>id"`
+	Lines    []*OrderLine `xml:"order>lines"`
+	Warnings []string     `xml:"warnings"`
+}
+
+func TestClient_SOAPEncoding(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+					xmlns:soapenc="http://schemas.xmlsoap.org/soap/encoding/"
+					xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+				<soapenv:Body soapenv:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">
+					<ns1:PlaceOrderResponse xmlns:ns1="urn:orders">
+						<order href="#id0"/>
+						<warnings xsi:type="soapenc:Array" soapenc:arrayType="xsd:string[2]">
+							<item xsi:type="xsd:string">backordered</item>
+							<item xsi:type="xsd:string">split shipment</item>
+						</warnings>
+					</ns1:PlaceOrderResponse>
+					<multiRef id="id0" soapenc:root="0" xsi:type="ns2:Order" xmlns:ns2="urn:orders">
+						<id xsi:type="xsd:string">A-1</id>
+						<lines soapenc:arrayType="ns2:Line[2]">
+							<item href="#id1"/>
+							<item href="#id1"/>
+						</lines>
+					</multiRef>
+					<multiRef id="id1" soapenc:root="0" xsi:type="ns3:Line" xmlns:ns3="urn:orders">
+						<sku xsi:type="xsd:string">X-9</sku>
+						<quantity xsi:type="xsd:int">3</quantity>
+					</multiRef>
+				</soapenv:Body>
+			</soapenv:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL, proxy.WithSOAPEncoding())
+	request := &PlaceOrder{Customer: "ACME", Lines: []*OrderLine{{Sku: "X-9", Quantity: 3}}}
+	reply := &PlaceOrderResponse{}
+	err := client.Call("urn:PlaceOrder", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<ns1:PlaceOrder xmlns:soapenc="http://schemas.xmlsoap.org/soap/encoding/" `+
+		`soap:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/" xmlns:ns1="urn:orders">`)
+	assert.Contains(t, gotRequest, `<customer xsi:type="xsd:string">ACME</customer>`)
+	assert.Contains(t, gotRequest, `<lines xsi:type="soapenc:Array" soapenc:arrayType="ns1:Line[1]"><item xsi:type="ns1:Line">`+
+		`<sku xsi:type="xsd:string">X-9</sku><quantity xsi:type="xsd:int">3</quantity></item></lines>`)
+
+	assert.Equal(t, "A-1", reply.Id)
+	assert.Len(t, reply.Lines, 2)
+	assert.Equal(t, &OrderLine{Sku: "X-9", Quantity: 3}, reply.Lines[1])
+	assert.Equal(t, []string{"backordered", "split shipment"}, reply.Warnings)
+}
+
+func TestClient_SOAP12Encoding(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"
+					xmlns:enc="http://www.w3.org/2003/05/soap-encoding"
+					xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+				<env:Body>
+					<ns1:PlaceOrderResponse xmlns:ns1="urn:orders" env:encodingStyle="http://www.w3.org/2003/05/soap-encoding">
+						<order>
+							<id xsi:type="xsd:string">A-1</id>
+							<lines enc:itemType="ns1:Line" enc:arraySize="2">
+								<item enc:id="line1">
+									<sku xsi:type="xsd:string">X-9</sku>
+									<quantity xsi:type="xsd:int">3</quantity>
+								</item>
+								<item enc:ref="line1"/>
+							</lines>
+						</order>
+						<warnings enc:itemType="xsd:string" enc:arraySize="1">
+							<item>backordered</item>
+						</warnings>
+					</ns1:PlaceOrderResponse>
+				</env:Body>
+			</env:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL, proxy.WithSOAPEncoding(), proxy.WithSOAP12())
+	request := &PlaceOrder{Customer: "ACME", Lines: []*OrderLine{{Sku: "X-9", Quantity: 3}}}
+	reply := &PlaceOrderResponse{}
+	err := client.Call("urn:PlaceOrder", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<ns1:PlaceOrder xmlns:soapenc="http://www.w3.org/2003/05/soap-encoding" `+
+		`soap:encodingStyle="http://www.w3.org/2003/05/soap-encoding" xmlns:ns1="urn:orders">`)
+	assert.Contains(t, gotRequest, `<lines xsi:type="soapenc:Array" soapenc:itemType="ns1:Line" soapenc:arraySize="1">`)
+
+	assert.Equal(t, "A-1", reply.Id)
+	assert.Len(t, reply.Lines, 2)
+	assert.Equal(t, &OrderLine{Sku: "X-9", Quantity: 3}, reply.Lines[1])
+	assert.Equal(t, []string{"backordered"}, reply.Warnings)
+}
+
+type QuoteRequest struct {
+	Symbol  string        `xml:"urn:quotes symbol"`
+	Options *QuoteOptions `xml:"urn:quotes options"`
+}
+
+type QuoteOptions struct {
+	Currency string `xml:"currency"`
+}
+
+func (m *QuoteRequest) BodyElements() []soap.BodyElement {
+	return []soap.BodyElement{
+		{Name: xml.Name{Space: "urn:quotes", Local: "symbol"}, Value: &m.Symbol},
+		{Name: xml.Name{Space: "urn:quotes", Local: "options"}, Value: &m.Options},
+	}
+}
+
+type QuoteResponse struct {
+	Price    float64 `xml:"urn:quotes price"`
+	Currency string  `xml:"urn:quotes currency"`
+}
+
+func (m *QuoteResponse) BodyElements() []soap.BodyElement {
+	return []soap.BodyElement{
+		{Name: xml.Name{Space: "urn:quotes", Local: "price"}, Value: &m.Price},
+		{Name: xml.Name{Space: "urn:quotes", Local: "currency"}, Value: &m.Currency},
+	}
+}
+
+func TestClient_BodyElements(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<currency xmlns="urn:quotes">EUR</currency>
+					<price xmlns="urn:quotes">12.5</price>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL, proxy.WithUnqualifiedElements())
+	reply := &QuoteResponse{}
+	err := client.Call("urn:GetQuote", &QuoteRequest{Symbol: "ACME", Options: &QuoteOptions{Currency: "EUR"}}, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<soap:Body><symbol xmlns="urn:quotes">ACME</symbol>`+
+		`<options xmlns="urn:quotes"><currency xmlns="">EUR</currency></options></soap:Body>`)
+	assert.Equal(t, &QuoteResponse{Price: 12.5, Currency: "EUR"}, reply)
+}
+
+func TestClient_BodyElementsRejectUnexpectedElements(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<price xmlns="urn:quotes">12.5</price>
+					<currency xmlns="urn:rates">EUR</currency>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	err := client.Call("urn:GetQuote", &QuoteRequest{Symbol: "ACME"}, &QuoteResponse{})
+	assert.EqualError(t, err, "Found unexpected element {urn:rates}currency inside SOAP body")
+}
+
+var shapeTypes = soap.NewTypeRegistry()
+
+type Shape struct {
+	Color string `xml:"urn:shapes color"`
+}
+
+type Circle struct {
+	*Shape
+	Radius float64 `xml:"urn:shapes radius"`
+}
+
+type Square struct {
+	XMLName xml.Name `xml:"urn:shapes square"`
+	*Shape
+	Side float64 `xml:"urn:shapes side"`
+}
+
+type ShapeInterface interface {
+	isShape()
+}
+
+func (*Shape) isShape()  {}
+func (*Circle) isShape() {}
+func (*Square) isShape() {}
+
+type AnyShape struct {
+	Value ShapeInterface
+}
+
+func (a AnyShape) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return shapeTypes.Marshal(e, start, a.Value, (*Shape)(nil))
+}
+
+func (a *AnyShape) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return shapeTypes.Unmarshal(d, start, &a.Value, (*Shape)(nil))
+}
+
+type Drawing struct {
+	XMLName xml.Name    `xml:"urn:shapes drawing"`
+	Shapes  []*AnyShape `xml:"urn:shapes shape"`
+}
+
+func init() {
+	shapeTypes.Register(xml.Name{Space: "urn:shapes", Local: "Shape"}, (*Shape)(nil))
+	shapeTypes.Register(xml.Name{Space: "urn:shapes", Local: "Circle"}, (*Circle)(nil))
+	shapeTypes.Register(xml.Name{Space: "urn:shapes", Local: "Square"}, (*Square)(nil))
+}
+
+func TestClient_XSITypePolymorphism(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"
+					xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:s="urn:shapes">
+				<soap:Body>
+					<s:drawing>
+						<s:shape xsi:type="s:Square"><s:color>red</s:color><s:side>2</s:side></s:shape>
+						<s:shape><s:color>blue</s:color></s:shape>
+						<s:shape xsi:type="Circle"><s:color>green</s:color><s:radius>1.5</s:radius></s:shape>
+					</s:drawing>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	request := &Drawing{Shapes: []*AnyShape{
+		{Value: &Circle{Shape: &Shape{Color: "red"}, Radius: 3}},
+		{Value: &Shape{Color: "blue"}},
+	}}
+	reply := &Drawing{}
+	err := client.Call("urn:Draw", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<shape xmlns="urn:shapes" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" `+
+		`xsi:type="Circle"><color xmlns="urn:shapes">red</color>`+
+		`<radius xmlns="urn:shapes">3</radius></shape>`)
+	assert.Contains(t, gotRequest, `<shape xmlns="urn:shapes"><color xmlns="urn:shapes">blue</color></shape>`)
+
+	assert.Len(t, reply.Shapes, 3)
+	assert.Equal(t, &Square{XMLName: xml.Name{Space: "urn:shapes", Local: "square"}, Shape: &Shape{Color: "red"}, Side: 2}, reply.Shapes[0].Value)
+	assert.Equal(t, &Shape{Color: "blue"}, reply.Shapes[1].Value)
+	assert.Equal(t, &Circle{Shape: &Shape{Color: "green"}, Radius: 1.5}, reply.Shapes[2].Value)
+}
+
+type Canvas struct {
+	XMLName xml.Name    `xml:"urn:canvas canvas"`
+	Shapes  []*AnyShape `xml:"urn:canvas shape"`
+}
+
+func TestTypeRegistry_MarshalDeclaresAPrefixPerElement(t *testing.T) {
+	canvas := &Canvas{Shapes: []*AnyShape{
+		{Value: &Circle{Shape: &Shape{Color: "red"}, Radius: 3}},
+		{Value: &Square{Shape: &Shape{Color: "blue"}, Side: 2}},
+	}}
+
+	data, err := xml.Marshal(canvas)
+	assert.NoError(t, err)
+
+	declarations := regexp.MustCompile(`xmlns:(\w+)="urn:shapes" xsi:type="(\w+):(\w+)"`).FindAllStringSubmatch(string(data), -1)
+	assert.Len(t, declarations, 2)
+	assert.NotContains(t, string(data), "tns")
+
+	for i, local := range []string{"Circle", "Square"} {
+		assert.Equal(t, declarations[i][1], declarations[i][2])
+		assert.Equal(t, local, declarations[i][3])
+	}
+	assert.NotEqual(t, declarations[0][1], declarations[1][1])
+
+	decoded := &Canvas{}
+	assert.NoError(t, xml.Unmarshal(data, decoded))
+	assert.Len(t, decoded.Shapes, 2)
+	assert.Equal(t, &Circle{Shape: &Shape{Color: "red"}, Radius: 3}, decoded.Shapes[0].Value)
+	assert.Equal(t, &Square{XMLName: xml.Name{Space: "urn:shapes", Local: "square"}, Shape: &Shape{Color: "blue"}, Side: 2}, decoded.Shapes[1].Value)
+}
+
+var pathElements = soap.NewElementRegistry()
+
+type FieldPath struct {
+	FieldURI string `xml:"FieldURI,attr"`
+}
+
+type IndexedFieldPath struct {
+	FieldURI   string `xml:"FieldURI,attr"`
+	FieldIndex string `xml:"FieldIndex,attr"`
+}
+
+type FieldURI FieldPath
+
+type IndexedFieldURI IndexedFieldPath
+
+type PathMember interface {
+	isPathMember()
+}
+
+func (*FieldURI) isPathMember()        {}
+func (*IndexedFieldURI) isPathMember() {}
+
+type PathChoice struct {
+	XMLName xml.Name
+	Value   PathMember
+}
+
+func (c PathChoice) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return pathElements.Marshal(e, c.XMLName, c.Value)
+}
+
+func (c *PathChoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	c.XMLName = start.Name
+	return pathElements.Unmarshal(d, start, &c.Value)
+}
+
+type GetProperties struct {
+	XMLName xml.Name      `xml:"urn:properties GetProperties"`
+	Path    []*PathChoice `xml:",any"`
+}
+
+func init() {
+	pathElements.Register(xml.Name{Space: "urn:properties", Local: "FieldURI"}, (*FieldURI)(nil))
+	pathElements.Register(xml.Name{Space: "urn:properties", Local: "IndexedFieldURI"}, (*IndexedFieldURI)(nil))
+}
+
+func TestClient_SubstitutionGroups(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<GetProperties xmlns="urn:properties">
+						<IndexedFieldURI FieldURI="contacts:PhoneNumber" FieldIndex="Home"/>
+						<Unknown/>
+						<FieldURI FieldURI="item:Subject"/>
+					</GetProperties>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	request := &GetProperties{Path: []*PathChoice{
+		{Value: &FieldURI{FieldURI: "item:Subject"}},
+		{XMLName: xml.Name{Space: "urn:properties", Local: "IndexedFieldURI"}, Value: &IndexedFieldURI{FieldURI: "contacts:PhoneNumber", FieldIndex: "Home"}},
+	}}
+	reply := &GetProperties{}
+	err := client.Call("urn:GetProperties", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<GetProperties xmlns="urn:properties">`+
+		`<FieldURI xmlns="urn:properties" FieldURI="item:Subject"></FieldURI>`+
+		`<IndexedFieldURI xmlns="urn:properties" FieldURI="contacts:PhoneNumber" FieldIndex="Home"></IndexedFieldURI>`+
+		`</GetProperties>`)
+
+	assert.Len(t, reply.Path, 3)
+	assert.Equal(t, &IndexedFieldURI{FieldURI: "contacts:PhoneNumber", FieldIndex: "Home"}, reply.Path[0].Value)
+	assert.Nil(t, reply.Path[1].Value)
+	assert.Equal(t, &FieldURI{FieldURI: "item:Subject"}, reply.Path[2].Value)
+	assert.Equal(t, xml.Name{Space: "urn:properties", Local: "FieldURI"}, reply.Path[2].XMLName)
+}
+
+type CartLine struct {
+	Product string   `xml:"urn:orders Product,omitempty"`
+	Service string   `xml:"urn:orders Service,omitempty"`
+	Note    []string `xml:"urn:orders Note,omitempty"`
+}
+
+type CartLines []*CartLine
+
+func (g CartLines) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return soap.MarshalModelGroup(e, g)
+}
+
+func (g *CartLines) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return soap.UnmarshalModelGroup(d, start, g)
+}
+
+type Checkout struct {
+	XMLName xml.Name  `xml:"urn:orders Checkout"`
+	Number  string    `xml:"urn:orders Number,omitempty"`
+	Lines   CartLines `xml:",any"`
+	Total   string    `xml:"urn:orders Total,omitempty"`
+}
+
+func TestClient_RepeatedModelGroups(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Checkout xmlns="urn:orders">
+						<Number>42</Number>
+						<Product>Book</Product>
+						<Note>Gift</Note>
+						<Note>Wrapped</Note>
+						<Product>Pen</Product>
+						<Unknown/>
+						<Service>Delivery</Service>
+						<Product>Ink</Product>
+						<Total>12</Total>
+					</Checkout>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	request := &Checkout{Number: "42", Lines: CartLines{{Product: "Book", Note: []string{"Gift"}}, {Service: "Delivery"}}, Total: "12"}
+	reply := &Checkout{}
+	err := client.Call("urn:Checkout", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<Number xmlns="urn:orders">42</Number>`+
+		`<Product xmlns="urn:orders">Book</Product><Note xmlns="urn:orders">Gift</Note>`+
+		`<Service xmlns="urn:orders">Delivery</Service>`+
+		`<Total xmlns="urn:orders">12</Total>`)
+
+	assert.Equal(t, CartLines{
+		{Product: "Book", Note: []string{"Gift", "Wrapped"}},
+		{Product: "Pen", Service: "Delivery"},
+		{Product: "Ink"},
+	}, reply.Lines)
+	assert.Equal(t, "12", reply.Total)
+}
+
+type ShippingStop struct {
+	Street string `xml:"urn:orders Street"`
+	City   string `xml:"urn:orders City"`
+}
+
+type ShippingStops []*ShippingStop
+
+func (g ShippingStops) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return soap.MarshalModelGroup(e, g)
+}
+
+func (g *ShippingStops) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return soap.UnmarshalModelGroup(d, start, g)
+}
+
+type ShipmentContent struct {
+	Lines CartLines     `xml:",any"`
+	Label []string      `xml:"urn:orders Label"`
+	Stops ShippingStops `xml:",any"`
+}
+
+func (c ShipmentContent) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return soap.MarshalGroupContent(e, &c)
+}
+
+func (c *ShipmentContent) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return soap.UnmarshalGroupContent(d, start, c)
+}
+
+type Shipment struct {
+	XMLName xml.Name         `xml:"urn:orders Shipment"`
+	Number  string           `xml:"urn:orders Number,omitempty"`
+	Content *ShipmentContent `xml:",any"`
+}
+
+func TestClient_SeveralRepeatedModelGroups(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Shipment xmlns="urn:orders">
+						<Number>7</Number>
+						<Product>Book</Product>
+						<Product>Pen</Product>
+						<Label>Fragile</Label>
+						<Street>Main St</Street>
+						<City>Springfield</City>
+						<Street>Elm St</Street>
+						<City>Shelbyville</City>
+					</Shipment>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	request := &Shipment{Number: "7", Content: &ShipmentContent{
+		Lines: CartLines{{Product: "Book"}},
+		Label: []string{"Fragile"},
+		Stops: ShippingStops{{Street: "Main St", City: "Springfield"}},
+	}}
+	reply := &Shipment{}
+	err := client.Call("urn:Ship", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<Number xmlns="urn:orders">7</Number>`+
+		`<Product xmlns="urn:orders">Book</Product><Label xmlns="urn:orders">Fragile</Label>`+
+		`<Street xmlns="urn:orders">Main St</Street><City xmlns="urn:orders">Springfield</City>`)
+
+	assert.Equal(t, &ShipmentContent{
+		Lines: CartLines{{Product: "Book"}, {Product: "Pen"}},
+		Label: []string{"Fragile"},
+		Stops: ShippingStops{{Street: "Main St", City: "Springfield"}, {Street: "Elm St", City: "Shelbyville"}},
+	}, reply.Content)
+}
+
+type Reading struct {
+	XMLName  xml.Name                   `xml:"urn:meters Reading"`
+	Meter    string                     `xml:"urn:meters Meter"`
+	Value    xsd.Nillable[int32]        `xml:"urn:meters Value"`
+	Taken    xsd.Nillable[xsd.DateTime] `xml:"urn:meters Taken"`
+	Previous xsd.Nillable[int32]        `xml:"urn:meters Previous"`
+}
+
+func TestClient_NillableElements(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Reading xmlns="urn:meters" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+						<Meter>M1</Meter>
+						<Value>0</Value>
+						<Taken xsi:nil="true"/>
+					</Reading>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	request := &Reading{Meter: "M1", Value: xsd.NewNillable[int32](0), Taken: xsd.NewNil[xsd.DateTime]()}
+	reply := &Reading{}
+	err := client.Call("urn:Read", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<Value xmlns="urn:meters">0</Value>`+
+		`<Taken xmlns="urn:meters" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></Taken></Reading>`)
+
+	value, ok := reply.Value.Get()
+	assert.True(t, ok)
+	assert.Equal(t, int32(0), value)
+	assert.True(t, reply.Taken.IsNil())
+	assert.True(t, reply.Previous.IsAbsent())
+}
+
+func TestClient_EncodedNillableElements(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Reading xmlns="urn:meters"><Meter>M1</Meter></Reading>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL, proxy.WithSOAPEncoding())
+	request := &Reading{Meter: "M1", Value: xsd.NewNillable[int32](7), Taken: xsd.NewNil[xsd.DateTime]()}
+	err := client.Call("urn:Read", request, &Reading{})
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `>7</`)
+	assert.Contains(t, gotRequest, `:Taken xsi:nil="true"></`)
+	assert.NotContains(t, gotRequest, `Previous`)
+}
+
+type Weekday string
+
+type Weekdays []Weekday
+
+func (l Weekdays) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return xsd.ListOf[Weekday](l).MarshalXML(e, start)
+}
+
+func (l Weekdays) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xsd.ListOf[Weekday](l).MarshalXMLAttr(name)
+}
+
+func (l *Weekdays) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return (*xsd.ListOf[Weekday])(l).UnmarshalXML(d, start)
+}
+
+func (l *Weekdays) UnmarshalXMLAttr(attr xml.Attr) error {
+	return (*xsd.ListOf[Weekday])(l).UnmarshalXMLAttr(attr)
+}
+
+type Schedule struct {
+	XMLName  xml.Name             `xml:"urn:schedule Schedule"`
+	Days     Weekdays             `xml:"urn:schedule Days"`
+	Holidays xsd.ListOf[xsd.Date] `xml:"urn:schedule Holidays,omitempty"`
+	Slots    xsd.ListOf[int32]    `xml:"urn:schedule Slots"`
+	Closed   *Weekdays            `xml:"closed,attr,omitempty"`
+	Ratios   xsd.ListOf[float64]  `xml:"ratios,attr,omitempty"`
+}
+
+func TestClient_ListTypes(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Schedule xmlns="urn:schedule" closed=" Tue  Mon ">
+						<Days>Mon
+							Tue</Days>
+						<Holidays>2026-12-25 2027-01-01</Holidays>
+						<Slots/>
+					</Schedule>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	request := &Schedule{
+		Days:     Weekdays{"Mon", "Tue"},
+		Holidays: xsd.ListOf[xsd.Date]{*xsd.NewDate(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), false)},
+		Slots:    xsd.ListOf[int32]{9, 14},
+		Ratios:   xsd.ListOf[float64]{0.5, 1},
+	}
+	reply := &Schedule{}
+	err := client.Call("urn:SetSchedule", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<Schedule xmlns="urn:schedule" ratios="0.5 1">`+
+		`<Days xmlns="urn:schedule">Mon Tue</Days>`+
+		`<Holidays xmlns="urn:schedule">2026-12-25</Holidays>`+
+		`<Slots xmlns="urn:schedule">9 14</Slots></Schedule>`)
+
+	assert.Equal(t, Weekdays{"Mon", "Tue"}, reply.Days)
+	assert.Equal(t, &Weekdays{"Tue", "Mon"}, reply.Closed)
+	assert.Len(t, reply.Holidays, 2)
+	assert.Equal(t, time.January, reply.Holidays[1].Time().Month())
+	assert.Empty(t, reply.Slots)
+	assert.Empty(t, reply.Ratios)
+}
+
+type SizeLabel string
+
+type Size struct {
+	member int
+	value  interface{}
+}
+
+func NewSizeInt(v int32) Size {
+	return Size{member: 0, value: v}
+}
+
+func (u Size) Int() (int32, bool) {
+	v, ok := u.value.(int32)
+	return v, ok && u.member == 0
+}
+
+func (u Size) SizeLabel() (SizeLabel, bool) {
+	v, ok := u.value.(SizeLabel)
+	return v, ok && u.member == 1
+}
+
+func (u Size) Unknown() (string, bool) {
+	v, ok := u.value.(string)
+	return v, ok && u.member == 2
+}
+
+func (u Size) Date() (xsd.Date, bool) {
+	v, ok := u.value.(xsd.Date)
+	return v, ok && u.member == 3
+}
+
+func (u Size) MarshalText() ([]byte, error) {
+	return xsd.FormatUnion(u.value)
+}
+
+func (u *Size) UnmarshalText(text []byte) error {
+	member, value, err := xsd.ParseUnion(string(text),
+		xsd.UnionMember{Value: new(int32)},
+		xsd.UnionMember{Value: new(SizeLabel), Enumeration: []string{"small", "large"}},
+		xsd.UnionMember{Value: new(string), Enumeration: []string{"unknown"}},
+		xsd.UnionMember{Value: new(xsd.Date)},
+	)
+	if err != nil {
+		return err
+	}
+
+	u.member, u.value = member, value
+	return nil
+}
+
+type Garment struct {
+	XMLName  xml.Name `xml:"urn:sizing Garment"`
+	Size     Size     `xml:"urn:sizing Size"`
+	Labelled Size     `xml:"urn:sizing Labelled"`
+	Unknown  Size     `xml:"urn:sizing Unknown"`
+	Released Size     `xml:"urn:sizing Released"`
+	Fit      *Size    `xml:"fit,attr,omitempty"`
+}
+
+func TestClient_UnionTypes(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Garment xmlns="urn:sizing" fit="large">
+						<Size> 42 </Size>
+						<Labelled>small</Labelled>
+						<Unknown>unknown</Unknown>
+						<Released>2026-03-01</Released>
+					</Garment>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	fit := NewSizeInt(38)
+	reply := &Garment{}
+	err := client.Call("urn:Order", &Garment{Size: NewSizeInt(42), Fit: &fit}, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<Garment xmlns="urn:sizing" fit="38"><Size xmlns="urn:sizing">42</Size>`)
+
+	size, ok := reply.Size.Int()
+	assert.True(t, ok)
+	assert.Equal(t, int32(42), size)
+
+	label, ok := reply.Fit.SizeLabel()
+	assert.True(t, ok)
+	assert.Equal(t, SizeLabel("large"), label)
+
+	_, ok = reply.Labelled.Unknown()
+	assert.False(t, ok)
+
+	unknown, ok := reply.Unknown.Unknown()
+	assert.True(t, ok)
+	assert.Equal(t, "unknown", unknown)
+
+	released, ok := reply.Released.Date()
+	assert.True(t, ok)
+	assert.Equal(t, time.March, released.Time().Month())
+
+	text, err := reply.Released.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-03-01", string(text))
+
+	assert.Error(t, new(Size).UnmarshalText([]byte("medium")))
+}
+
+type InvoiceVersion string
+
+func (a InvoiceVersion) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xsd.MarshalFixedAttr(name, string(a), InvoiceVersionFixed)
+}
+
+type Invoice struct {
+	XMLName  xml.Name           `xml:"urn:billing Invoice"`
+	Amount   float64            `xml:"urn:billing Amount"`
+	Currency *string            `xml:"urn:billing Currency,omitempty"`
+	Paid     xsd.Nillable[bool] `xml:"urn:billing Paid"`
+	Version  InvoiceVersion     `xml:"version,attr"`
+	Priority int32              `xml:"priority,attr,omitempty"`
+}
+
+const (
+	InvoiceAmountDefault   = "0.5"
+	InvoiceCurrencyDefault = "EUR"
+	InvoicePaidDefault     = "false"
+	InvoiceVersionFixed    = "1.0"
+	InvoicePriorityDefault = "3"
+)
+
+func NewInvoice() *Invoice {
+	t := &Invoice{}
+	xsd.SetValue(&t.Amount, InvoiceAmountDefault)
+	xsd.SetValue(&t.Currency, InvoiceCurrencyDefault)
+	xsd.SetValue(&t.Paid, InvoicePaidDefault)
+	xsd.SetValue(&t.Version, InvoiceVersionFixed)
+	xsd.SetValue(&t.Priority, InvoicePriorityDefault)
+	return t
+}
+
+func TestClient_DefaultAndFixedValues(t *testing.T) {
+	var gotRequests []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequests = append(gotRequests, string(body))
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Invoice xmlns="urn:billing" version="1.0"><Amount>1</Amount><Paid>true</Paid></Invoice>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	invoice := NewInvoice()
+	assert.Equal(t, 0.5, invoice.Amount)
+	assert.Equal(t, "EUR", *invoice.Currency)
+	assert.Equal(t, xsd.NewNillable(false), invoice.Paid)
+	assert.Equal(t, InvoiceVersion("1.0"), invoice.Version)
+	assert.Equal(t, int32(3), invoice.Priority)
+
+	client := proxy.NewClient(ts.URL)
+	reply := &Invoice{}
+	err := client.Call("urn:Bill", invoice, reply)
+	assert.NoError(t, err)
+
+	// fixed attributes are written even when left empty
+	err = client.Call("urn:Bill", &Invoice{Paid: xsd.NewNil[bool]()}, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequests[0], `<Invoice xmlns="urn:billing" version="1.0" priority="3"><Amount xmlns="urn:billing">0.5</Amount><Currency xmlns="urn:billing">EUR</Currency><Paid xmlns="urn:billing">false</Paid></Invoice>`)
+	assert.Contains(t, gotRequests[1], `<Invoice xmlns="urn:billing" version="1.0"><Amount xmlns="urn:billing">0</Amount>`)
+
+	// values which are not valid for their field leave it unset
+	var amount float64
+	xsd.SetValue(&amount, "many")
+	assert.Equal(t, 0.0, amount)
+}
+
+type Paragraph struct {
+	Lang    string          `xml:"lang,attr,omitempty"`
+	Content []xsd.MixedNode `xml:",any"`
+}
+
+func (t *Paragraph) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return xsd.UnmarshalMixed(d, start, t,
+		xsd.MixedElement{Name: xml.Name{Space: "urn:notes", Local: "b"}, Value: new(string)},
+		xsd.MixedElement{Name: xml.Name{Space: "urn:notes", Local: "count"}, Value: new(int32)},
+	)
+}
+
+func (t Paragraph) B() []string {
+	return xsd.MixedElements[string](t.Content, xml.Name{Space: "urn:notes", Local: "b"})
+}
+
+type Note struct {
+	XMLName xml.Name `xml:"urn:notes Note"`
+	*Paragraph
+	Author string `xml:"author,attr,omitempty"`
+}
+
+func (t *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return xsd.UnmarshalMixed(d, start, t,
+		xsd.MixedElement{Name: xml.Name{Space: "urn:notes", Local: "b"}, Value: new(string)},
+		xsd.MixedElement{Name: xml.Name{Space: "urn:notes", Local: "count"}, Value: new(int32)},
+		xsd.MixedElement{Name: xml.Name{Space: "urn:notes", Local: "signature"}, Value: new(string)},
+	)
+}
+
+func TestClient_MixedContent(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Note xmlns="urn:notes" lang="en" author="Ann">Buy <count>3</count> <b>red</b> apples<!-- or pears -->, ` +
+			`<em class="soon">today</em>.<signature>A.</signature></Note>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	note := &Note{
+		Paragraph: &Paragraph{Content: []xsd.MixedNode{
+			xsd.TextNode("Call "),
+			xsd.ElementNode(xml.Name{Space: "urn:notes", Local: "b"}, "Bob"),
+			xsd.TextNode(" back"),
+		}},
+		Author: "Ann",
+	}
+
+	client := proxy.NewClient(ts.URL)
+	reply := &Note{}
+	err := client.Call("urn:Memo", note, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<Note xmlns="urn:notes" author="Ann">Call <b xmlns="urn:notes">Bob</b> back</Note>`)
+
+	assert.Equal(t, "en", reply.Lang)
+	assert.Equal(t, "Ann", reply.Author)
+	assert.Equal(t, []string{"red"}, reply.B())
+	assert.Equal(t, []xsd.MixedNode{
+		xsd.TextNode("Buy "),
+		xsd.ElementNode(xml.Name{Space: "urn:notes", Local: "count"}, int32(3)),
+		xsd.TextNode(" "),
+		xsd.ElementNode(xml.Name{Space: "urn:notes", Local: "b"}, "red"),
+		xsd.TextNode(" apples, "),
+		xsd.ElementNode(xml.Name{Space: "urn:notes", Local: "em"}, &xsd.Node{
+			XMLName:  xml.Name{Space: "urn:notes", Local: "em"},
+			Attrs:    []xml.Attr{{Name: xml.Name{Local: "class"}, Value: "soon"}},
+			Children: []*xsd.Node{{Text: "today"}},
+		}),
+		xsd.TextNode("."),
+		xsd.ElementNode(xml.Name{Space: "urn:notes", Local: "signature"}, "A."),
+	}, reply.Content)
+
+	// the content is written back as it was read
+	data, err := xml.Marshal(reply)
+	assert.NoError(t, err)
+
+	again := &Note{}
+	assert.NoError(t, xml.Unmarshal(data, again))
+	assert.Equal(t, reply, again)
+}
+
+type Record struct {
+	XMLName  xml.Name    `xml:"urn:records Record"`
+	Id       int32       `xml:"urn:records Id"`
+	Items    []*xsd.Node `xml:",any"`
+	AnyAttrs []xml.Attr  `xml:",any,attr"`
+}
+
+type Location struct {
+	XMLName xml.Name `xml:"urn:places Location"`
+	City    string   `xml:"urn:places City"`
+	Code    string   `xml:"code,attr,omitempty"`
+}
+
+var globalElements = soap.NewElementRegistry()
+
+func init() {
+	globalElements.Register(xml.Name{Space: "urn:places", Local: "Location"}, (*Location)(nil))
+}
+
+func TestClient_Wildcards(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<Record xmlns="urn:records" xmlns:m="urn:meta" m:source="feed" version="2">` +
+			`<Id>7</Id><Location xmlns="urn:places" code="PAR"><City>Paris</City></Location>` +
+			`<m:note m:lang="fr">Bonjour <m:b>tous</m:b></m:note></Record>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	request := &Record{
+		Id: 1,
+		Items: []*xsd.Node{{
+			XMLName:    xml.Name{Space: "urn:meta", Local: "note"},
+			Attrs:      []xml.Attr{{Name: xml.Name{Space: "urn:meta", Local: "lang"}, Value: "en"}},
+			Namespaces: []xml.Attr{{Name: xml.Name{Local: "meta"}, Value: "urn:meta"}},
+			Children:   []*xsd.Node{{Text: "Hello"}},
+		}},
+		AnyAttrs: []xml.Attr{{Name: xml.Name{Local: "version"}, Value: "1"}},
+	}
+
+	client := proxy.NewClient(ts.URL)
+	reply := &Record{}
+	err := client.Call("urn:Record", request, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<Record xmlns="urn:records" version="1"><Id xmlns="urn:records">1</Id>`+
+		`<note xmlns="urn:meta" xmlns:meta="urn:meta" meta:lang="en">Hello</note></Record>`)
+
+	assert.Equal(t, int32(7), reply.Id)
+	// namespace declarations are attributes to encoding/xml
+	assert.Equal(t, []xml.Attr{
+		{Name: xml.Name{Local: "xmlns"}, Value: "urn:records"},
+		{Name: xml.Name{Space: "xmlns", Local: "m"}, Value: "urn:meta"},
+		{Name: xml.Name{Space: "urn:meta", Local: "source"}, Value: "feed"},
+		{Name: xml.Name{Local: "version"}, Value: "2"},
+	}, reply.AnyAttrs)
+
+	if assert.Len(t, reply.Items, 2) {
+		location, err := globalElements.Decode(reply.Items[0])
+		assert.NoError(t, err)
+		assert.Equal(t, &Location{XMLName: xml.Name{Space: "urn:places", Local: "Location"}, City: "Paris", Code: "PAR"}, location)
+
+		note := reply.Items[1]
+		assert.Equal(t, xml.Name{Space: "urn:meta", Local: "note"}, note.XMLName)
+		lang, ok := note.Attr(xml.Name{Local: "lang"})
+		assert.True(t, ok)
+		assert.Equal(t, "fr", lang)
+		assert.Equal(t, "Bonjour tous", note.InnerText())
+		assert.Len(t, note.Elements(xml.Name{Space: "urn:meta", Local: "b"}), 1)
+
+		_, err = globalElements.Decode(note)
+		assert.Error(t, err)
+	}
+
+	// the content is written back as it was read
+	data, err := xml.Marshal(reply)
+	assert.NoError(t, err)
+
+	again := &Record{}
+	assert.NoError(t, xml.Unmarshal(data, again))
+	assert.Equal(t, reply.Items, again.Items)
+}
+
+type Parcel struct {
+	XMLName   xml.Name `xml:"urn:shipping Ship"`
+	Reference string   `xml:"Reference"`
+	Carrier   string   `xml:"urn:shipping Carrier"`
+	Weight    *Weight  `xml:"urn:units Weight"`
+	Priority  int32    `xml:"urn:shipping priority,attr,omitempty"`
+	Lang      string   `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+}
+
+type Weight struct {
+	Value float64 `xml:",chardata"`
+	Scale string  `xml:"Scale,omitempty"`
+}
+
+func TestClient_UnqualifiedElements(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(body)
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<s:Ship xmlns:s="urn:shipping" s:priority="2" xml:lang="fr">` +
+			`<Reference>B-2</Reference><s:Carrier>Post</s:Carrier></s:Ship>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	request := &Parcel{Reference: "A-1", Carrier: "Courier", Weight: &Weight{Value: 1.5, Scale: "kg"}, Priority: 1, Lang: "en"}
+
+	client := proxy.NewClient(ts.URL, proxy.WithUnqualifiedElements())
+	reply := &Parcel{}
+	err := client.Call("urn:Ship", request, reply)
+	assert.NoError(t, err)
+
+	// unqualified elements are taken out of the default namespace of their parent, qualified attributes are prefixed
+	assert.Contains(t, gotRequest, `<soap:Body><Ship xmlns="urn:shipping" xmlns:_="urn:shipping" _:priority="1" xml:lang="en">`+
+		`<Reference xmlns="">A-1</Reference><Carrier xmlns="urn:shipping">Courier</Carrier>`+
+		`<Weight xmlns="urn:units">1.5<Scale xmlns="">kg</Scale></Weight></Ship></soap:Body>`)
+
+	assert.Equal(t, &Parcel{
+		XMLName:   xml.Name{Space: "urn:shipping", Local: "Ship"},
+		Reference: "B-2",
+		Carrier:   "Post",
+		Priority:  2,
+		Lang:      "fr",
+	}, reply)
+
+	// without the option, the request is written as encoding/xml writes it
+	client = proxy.NewClient(ts.URL)
+	err = client.Call("urn:Ship", request, &Parcel{})
+	assert.NoError(t, err)
+	assert.Contains(t, gotRequest, `<Reference>A-1</Reference>`)
+}
+
+type UploadDocument struct {
+	XMLName  xml.Name      `xml:"urn:documents UploadDocument"`
+	Name     string        `xml:"Name"`
+	Document *proxy.Binary `xml:"Document"`
+}
+
+func TestClient_UnqualifiedElementsWithMTOM(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range r.Header {
+			w.Header().Set(k, v[0])
+		}
+		bodyBuf, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(bodyBuf)
+		_, _ = w.Write(bodyBuf)
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL, proxy.WithMTOM(), proxy.WithUnqualifiedElements())
+	req := &UploadDocument{Name: "report.txt", Document: proxy.NewBinary([]byte("Attached data")).SetContentType("text/plain")}
+	reply := &UploadDocument{}
+	err := client.Call("Upload", req, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<UploadDocument xmlns="urn:documents"><Name xmlns="">report.txt</Name><Document xmlns="">`+
+		`<Include xmlns="http://www.w3.org/2004/08/xop/include" href="cid:`)
+	assert.Contains(t, gotRequest, "Content-Type: text/plain")
+	assert.Equal(t, "report.txt", reply.Name)
+	if assert.NotNil(t, reply.Document) {
+		assert.Equal(t, []byte("Attached data"), reply.Document.Bytes())
+		assert.Equal(t, "text/plain", reply.Document.ContentType())
+	}
+}
+
+func TestClient_UnqualifiedElementsWithAttachments(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range r.Header {
+			w.Header().Set(k, v[0])
+		}
+		bodyBuf, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(bodyBuf)
+		_, _ = w.Write(bodyBuf)
+	}))
+	defer ts.Close()
+
+	attachment := soap.MIMEMultipartAttachment{Name: "First_Attachment", Data: []byte(`foobar`)}
+
+	client := proxy.NewClient(ts.URL, proxy.WithMIMEMultipartAttachments(), proxy.WithUnqualifiedElements())
+	client.AddMIMEMultipartAttachment(attachment)
+
+	req := &AttachmentRequest{Name: "UploadMyFilePlease", ContentID: "First_Attachment"}
+	reply := &AttachmentRequest{}
+	retAttachments := make([]soap.MIMEMultipartAttachment, 0)
+	err := client.CallContextWithAttachmentsAndFaultDetail(context.TODO(), "''", req, reply, nil, &retAttachments)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<attachmentRequest xmlns="http://example.com/service.xsd">`+
+		`<name xmlns="">UploadMyFilePlease</name><contentID xmlns="">First_Attachment</contentID></attachmentRequest>`)
+	assert.Equal(t, req.ContentID, reply.ContentID)
+	assert.Equal(t, []soap.MIMEMultipartAttachment{attachment}, retAttachments)
+}
+
+func TestClient_CallOneWay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	err := client.CallOneWayContext(context.Background(), "urn:Log", &Ping{Request: &PingRequest{Message: "Ping"}})
+	assert.NoError(t, err)
+}
+
+func TestClient_CallOptional(t *testing.T) {
+	var reply string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reply == "" {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		_, _ = w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` + reply + `</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	// the service may reply with nothing, an empty body, the response or a fault
+	tests := []struct {
+		reply   string
+		replied bool
+		err     string
+	}{
+		{reply: ""},
+		{reply: `<soap:Body/>`},
+		{reply: `<soap:Body><PingResponse xmlns="http://example.com/service.xsd"><PingResult><Message>Pong</Message>` +
+			`</PingResult></PingResponse></soap:Body>`, replied: true},
+		{reply: `<soap:Body><soap:Fault><faultcode>soap:Server</faultcode><faultstring>Not cached</faultstring>` +
+			`</soap:Fault></soap:Body>`, err: "Not cached"},
+	}
+
+	client := proxy.NewClient(ts.URL)
+	for _, test := range tests {
+		reply = test.reply
+		response := &PingResponse{}
+
+		replied, err := client.CallOptionalContext(context.Background(), "urn:GetCachedPing", &Ping{}, response)
+		assert.Equal(t, test.replied, replied)
+
+		if test.err != "" {
+			assert.EqualError(t, err, test.err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		if test.replied {
+			assert.Equal(t, "Pong", response.PingResult.Message)
+		}
+	}
+}
+
+func TestClient_CallWithoutResponseFails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body><PingResponse xmlns="http://example.com/service.xsd"/></soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	err := client.Call("GetData", &Ping{Request: &PingRequest{Message: "Ping"}}, nil)
+	assert.EqualError(t, err, "Content must be a pointer to a struct")
+}
+
+func TestClient_CallOneWayFault(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+				<soap:Body>
+					<soap:Fault>
+						<faultcode>soap:Client</faultcode>
+						<faultstring>Invalid symbol</faultstring>
+					</soap:Fault>
+				</soap:Body>
+			</soap:Envelope>`))
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL)
+	err := client.CallOneWayContext(context.Background(), "urn:Subscribe", &Ping{Request: &PingRequest{Message: "Ping"}})
+	assert.EqualError(t, err, "Invalid symbol")
+}
+
 func TestClient_Attachments_WithAttachmentResponse(t *testing.T) {
 	req := &AttachmentRequest{Name: "UploadMyFilePlease", ContentID: "First_Attachment"}
 
@@ -184,6 +1544,46 @@ func TestClient_MTOM(t *testing.T) {
 	}
 }
 
+type UploadRequest struct {
+	Name     string        `xml:"urn:documents Name"`
+	Document *proxy.Binary `xml:"urn:documents Document"`
+}
+
+func (m *UploadRequest) BodyElements() []soap.BodyElement {
+	return []soap.BodyElement{
+		{Name: xml.Name{Space: "urn:documents", Local: "Name"}, Value: &m.Name},
+		{Name: xml.Name{Space: "urn:documents", Local: "Document"}, Value: &m.Document},
+	}
+}
+
+func TestClient_MTOMBodyElements(t *testing.T) {
+	var gotRequest string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range r.Header {
+			w.Header().Set(k, v[0])
+		}
+		bodyBuf, _ := ioutil.ReadAll(r.Body)
+		gotRequest = string(bodyBuf)
+		_, _ = w.Write(bodyBuf)
+	}))
+	defer ts.Close()
+
+	client := proxy.NewClient(ts.URL, proxy.WithMTOM())
+	req := &UploadRequest{Name: "report.txt", Document: proxy.NewBinary([]byte("Attached data")).SetContentType("text/plain")}
+	reply := &UploadRequest{}
+	err := client.Call("Upload", req, reply)
+	assert.NoError(t, err)
+
+	assert.Contains(t, gotRequest, `<Include xmlns="http://www.w3.org/2004/08/xop/include" href="cid:`)
+	assert.Contains(t, gotRequest, "Content-Type: text/plain")
+	assert.Equal(t, "report.txt", reply.Name)
+	if assert.NotNil(t, reply.Document) {
+		assert.Equal(t, []byte("Attached data"), reply.Document.Bytes())
+		assert.Equal(t, "text/plain", reply.Document.ContentType())
+	}
+}
+
 type SimpleNode struct {
 	Detail string      `xml:"Detail,omitempty"`
 	Num    float64     `xml:"Num,omitempty"`
@@ -590,6 +1990,93 @@ func TestXsdTime(t *testing.T) {
 	}
 }
 
+type Grade string
+
+func (t Grade) Validate() error {
+	var v xsd.Validation
+	v.Facets("", t, xsd.Facets{Enumeration: []string{"A", "B"}})
+	return v.Err()
+}
+
+type Mark struct {
+	Grade   *Grade `xml:"Grade"`
+	Comment string `xml:"Comment,omitempty"`
+}
+
+func (t Mark) Validate() error {
+	var v xsd.Validation
+	v.Required("Grade", t.Grade)
+	v.Value("Grade", t.Grade)
+	v.Facets("Comment", t.Comment, xsd.Facets{MaxLength: "5"})
+	return v.Err()
+}
+
+type Report struct {
+	Student string               `xml:"student,attr,omitempty"`
+	Code    string               `xml:"Code"`
+	Score   []int32              `xml:"Score"`
+	Issued  *xsd.Date            `xml:"Issued,omitempty"`
+	Marks   []*Mark              `xml:"Mark"`
+	Remark  xsd.Nillable[string] `xml:"Remark"`
+}
+
+func (t Report) Validate() error {
+	var v xsd.Validation
+	v.Required("Student", t.Student)
+	v.Facets("Code", t.Code, xsd.Facets{Pattern: `[A-Z]{2}\d{2}`})
+	v.Occurs("Score", len(t.Score), 1, 2)
+	v.Facets("Score", t.Score, xsd.Facets{MinInclusive: "0", MaxInclusive: "100"})
+	v.Facets("Issued", t.Issued, xsd.Facets{MinInclusive: "2020-01-01"})
+	v.Occurs("Marks", len(t.Marks), 1, -1)
+	v.Value("Marks", t.Marks)
+	v.Required("Remark", t.Remark)
+	return v.Err()
+}
+
+func TestXsdValidation(t *testing.T) {
+	good, bad := Grade("A"), Grade("C")
+
+	valid := Report{
+		Student: "Ann",
+		Code:    "AB12",
+		Score:   []int32{90},
+		Issued:  xsd.NewDate(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), false),
+		Marks:   []*Mark{{Grade: &good}},
+		Remark:  xsd.NewNil[string](),
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := Report{
+		Code:   "ab12",
+		Score:  []int32{90, 101, 5},
+		Issued: xsd.NewDate(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC), false),
+		Marks:  []*Mark{{Grade: &good}, {Comment: "too long"}, {Grade: &bad}},
+	}
+
+	err := invalid.Validate()
+	assert.Error(t, err)
+
+	errs, ok := err.(xsd.ValidationErrors)
+	assert.True(t, ok)
+
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.Error())
+	}
+
+	assert.Equal(t, []string{
+		"Student: is required",
+		`Code: "ab12" does not match the pattern "[A-Z]{2}\\d{2}"`,
+		"Score: occurs 3 times, at most 2 expected",
+		"Score[1]: 101 is greater than 100",
+		"Issued: 2019-06-01 is less than 2020-01-01",
+		"Marks[1].Grade: is required",
+		"Marks[1].Comment: has length 8, at most 5 expected",
+		`Marks[2].Grade: "C" is not one of the enumerated values`,
+		"Remark: is required",
+	}, paths)
+}
+
 func TestHTTPError(t *testing.T) {
 	type httpErrorTest struct {
 		name         string
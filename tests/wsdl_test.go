@@ -17,3 +17,114 @@ func TestUnmarshal(t *testing.T) {
 	err = xml.Unmarshal(data, &v)
 	assert.NoError(t, err)
 }
+
+func TestMergeSkipsComponentsAlreadyDeclared(t *testing.T) {
+	root := wsdl.WSDL{}
+	err := xml.Unmarshal([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" xmlns:tns="urn:root">
+			<types>
+				<schema xmlns="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:shared">
+					<element name="Shared" type="string"/>
+					<group name="Address"><sequence><element name="Street" type="string"/></sequence></group>
+					<attributeGroup name="Audit"><attribute name="by" type="string"/></attributeGroup>
+				</schema>
+			</types>
+			<message name="RootMessage"/>
+		</definitions>`), &root)
+	assert.NoError(t, err)
+
+	imported := wsdl.WSDL{}
+	err = xml.Unmarshal([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" xmlns:tns="urn:imported"
+				xmlns:imp="urn:imported" targetNamespace="urn:imported">
+			<types>
+				<schema xmlns="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:shared">
+					<element name="Shared" type="string"/>
+					<element name="Extra" type="string"/>
+					<group name="Address"><sequence><element name="Street" type="string"/></sequence></group>
+					<group name="Contact"><sequence><element name="Email" type="string"/></sequence></group>
+					<attributeGroup name="Audit"><attribute name="by" type="string"/></attributeGroup>
+				</schema>
+			</types>
+			<message name="ImportedMessage">
+				<part name="parameters" element="tns:Extra"/>
+				<part name="header" element="imp:Header"/>
+			</message>
+		</definitions>`), &imported)
+	assert.NoError(t, err)
+
+	root.Merge(&imported)
+
+	assert.Equal(t, "urn:imported", root.TargetNamespace)
+	assert.Equal(t, "urn:root", root.Xmlns["tns"])
+	assert.Len(t, root.Messages, 2)
+	assert.Equal(t, "ns1:Extra", root.Messages[1].Parts[0].Element)
+	assert.Equal(t, "ns1:Header", root.Messages[1].Parts[1].Element)
+	assert.Equal(t, "urn:imported", root.Xmlns["ns1"])
+	assert.Len(t, root.Types.Schemas, 2)
+	assert.Len(t, root.Types.Schemas[1].Elements, 1)
+	assert.Equal(t, "Extra", root.Types.Schemas[1].Elements[0].Name)
+	assert.Len(t, root.Types.Schemas[1].Groups, 1)
+	assert.Equal(t, "Contact", root.Types.Schemas[1].Groups[0].Name)
+	assert.Empty(t, root.Types.Schemas[1].AttributeGroups)
+}
+
+func TestMergePicksTheFirstPrefixOfANamespace(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		root := wsdl.WSDL{}
+		err := xml.Unmarshal([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" xmlns:b="urn:shared"
+				xmlns:a="urn:shared" xmlns:c="urn:shared"/>`), &root)
+		assert.NoError(t, err)
+
+		imported := wsdl.WSDL{}
+		err = xml.Unmarshal([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" xmlns:s="urn:shared">
+				<message name="Shared">
+					<part name="parameters" element="s:Shared"/>
+				</message>
+			</definitions>`), &imported)
+		assert.NoError(t, err)
+
+		root.Merge(&imported)
+
+		assert.Equal(t, "a:Shared", root.Messages[0].Parts[0].Element)
+	}
+}
+
+func TestMergeRequalifiesOperationsBindingsAndPorts(t *testing.T) {
+	root := wsdl.WSDL{}
+	err := xml.Unmarshal([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" xmlns:tns="urn:root"
+			targetNamespace="urn:root"/>`), &root)
+	assert.NoError(t, err)
+
+	imported := wsdl.WSDL{}
+	err = xml.Unmarshal([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"
+			xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/" xmlns:tns="urn:imported" targetNamespace="urn:imported">
+			<portType name="Quotes">
+				<operation name="GetQuote">
+					<input message="tns:GetQuoteRequest"/>
+					<output message="tns:GetQuoteResponse"/>
+					<fault name="Unknown" message="tns:UnknownSymbol"/>
+				</operation>
+			</portType>
+			<binding name="QuotesBinding" type="tns:Quotes">
+				<operation name="GetQuote">
+					<input>
+						<soap:header message="tns:Session" part="session" use="literal"/>
+					</input>
+				</operation>
+			</binding>
+			<service name="QuotesService">
+				<port name="QuotesPort" binding="tns:QuotesBinding"/>
+			</service>
+		</definitions>`), &imported)
+	assert.NoError(t, err)
+
+	root.Merge(&imported)
+
+	assert.Equal(t, "urn:imported", root.Xmlns["ns1"])
+	operation := root.PortTypes[0].Operations[0]
+	assert.Equal(t, "ns1:GetQuoteRequest", operation.Input.Message)
+	assert.Equal(t, "ns1:GetQuoteResponse", operation.Output.Message)
+	assert.Equal(t, "ns1:UnknownSymbol", operation.Faults[0].Message)
+	assert.Equal(t, "ns1:Quotes", root.Binding[0].Type)
+	assert.Equal(t, "ns1:Session", root.Binding[0].Operations[0].Input.SOAPHeader[0].Message)
+	assert.Equal(t, "ns1:QuotesBinding", root.Service[0].Ports[0].Binding)
+}